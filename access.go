@@ -0,0 +1,85 @@
+package acdb
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/godump/lru"
+)
+
+// Access describes how a key has been used since tracking was enabled.
+type Access struct {
+	// Last is the time of the last get or set.
+	Last time.Time
+	// Count is the number of gets and sets.
+	Count uint64
+}
+
+// Track enables tracking of accesses for at most size keys. When more keys are used, the least recently used ones are
+// forgotten, which bounds the memory used by tracking. A size of zero disables tracking.
+func (e *Client) Track(size int) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.tm.Lock()
+	defer e.tm.Unlock()
+	if size == 0 {
+		e.track = nil
+		return
+	}
+	e.track = lru.New[string, *Access](size, time.Duration(math.MaxInt64))
+}
+
+// access records an access to a key. The caller must hold the lock.
+func (e *Client) access(k string) {
+	if e.track == nil {
+		return
+	}
+	e.tm.Lock()
+	defer e.tm.Unlock()
+	a, b := e.track.GetExists(k)
+	if !b {
+		a = &Access{}
+		e.track.Set(k, a)
+	}
+	a.Last = time.Now()
+	a.Count++
+}
+
+// Stat returns the accesses of a key. If the key is not tracked, ErrNotExist is returned.
+func (e *Client) Stat(k string) (Access, error) {
+	e.tm.Lock()
+	defer e.tm.Unlock()
+	if e.track == nil {
+		return Access{}, ErrNotExist
+	}
+	a, b := e.track.GetExists(k)
+	if !b {
+		return Access{}, ErrNotExist
+	}
+	return *a, nil
+}
+
+// Idle returns the keys which have not been used since t, including the keys that are not tracked at all. The driver
+// must implement Scanner. This helps to find dead keys which are safe to delete.
+func (e *Client) Idle(t time.Time) ([]string, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	s, b := e.driver.(Scanner)
+	if !b {
+		return nil, ErrUnsupported
+	}
+	if e.track == nil {
+		return nil, errors.New("acdb: tracking is disabled")
+	}
+	r := []string{}
+	err := s.Scan(func(k string, v []byte) error {
+		e.tm.Lock()
+		defer e.tm.Unlock()
+		if a, b := e.track.C[k]; !b || a.V.Last.Before(t) {
+			r = append(r, k)
+		}
+		return nil
+	})
+	return r, err
+}
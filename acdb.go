@@ -1,11 +1,16 @@
 package acdb
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"hash/crc32"
 	"log"
 	"os"
 	"path"
+	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/godump/doa"
@@ -15,52 +20,104 @@ import (
 // Driver is the interface that wraps the Set/Get and Del method.
 //
 // Get gets and returns the bytes or any error encountered. If the key does not exist, ErrNotExist will be returned.
+// Drivers return ErrNotExist itself, not an error wrapping it, so callers may compare with == or errors.Is.
 // Set sets bytes with given k.
 // Del dels bytes with given k. If the key does not exist, ErrNotExist will be returned.
+//
+// A Client calls Get, and the reading methods of the optional interfaces such as Scan or Range, from several goroutines
+// at once, so they must be safe for concurrent use with each other. Set, Del and other writes are never concurrent with
+// any other call.
 type Driver interface {
 	Get(k string) ([]byte, error)
 	Set(k string, v []byte) error
 	Del(k string) error
 }
 
+// Scanner is implemented by drivers which are able to iterate over all their keys.
+//
+// Scan calls f for each key and its value. If f returns an error, the iteration stops and the error is returned.
+type Scanner interface {
+	Scan(f func(k string, v []byte) error) error
+}
+
+// Expirer is implemented by drivers whose keys expire.
+//
+// ExpiringBefore returns the keys which will expire before t, the soonest first.
+type Expirer interface {
+	ExpiringBefore(t time.Time) ([]string, error)
+}
+
 // MemDriver cares to store data on memory, this means that MemDriver is fast. Since there is no expiration mechanism,
 // be careful that it might eats up all your memory.
 type MemDriver struct {
 	data map[string][]byte
+	keys []string
+	m    *sync.RWMutex
 }
 
 // NewMemDriver returns a MemDriver.
 func NewMemDriver() *MemDriver {
 	return &MemDriver{
 		data: map[string][]byte{},
+		m:    &sync.RWMutex{},
 	}
 }
 
 // Get the value of a key.
 func (d *MemDriver) Get(k string) ([]byte, error) {
+	d.m.RLock()
+	defer d.m.RUnlock()
 	v, b := d.data[k]
 	if b {
 		return v, nil
 	}
-	return nil, os.ErrNotExist
+	return nil, ErrNotExist
 }
 
 // Set the value of a key.
 func (d *MemDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if _, b := d.data[k]; !b {
+		d.keys = nil
+	}
 	d.data[k] = v
 	return nil
 }
 
 // Del the value of a key.
 func (d *MemDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if _, b := d.data[k]; !b {
+		return ErrNotExist
+	}
+	d.keys = nil
 	delete(d.data, k)
 	return nil
 }
 
+// Scan calls f for each key and its value. The driver is read locked during the scan, so f must not write to it.
+func (d *MemDriver) Scan(f func(k string, v []byte) error) error {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	for k, v := range d.data {
+		if err := f(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DocDriver use the OS's file system to manage data. In general, any high frequency operation is not recommended
 // unless you have an enough reason.
 type DocDriver struct {
-	root string
+	root  string
+	lock  *os.File
+	shard bool
+	sum   bool
+	mmap  int64
+	fs    atomic.Pointer[syncer]
 }
 
 // NewDocDriver returns a DocDriver.
@@ -73,17 +130,91 @@ func NewDocDriver(root string) *DocDriver {
 
 // Get the value of a key.
 func (d *DocDriver) Get(k string) ([]byte, error) {
-	return os.ReadFile(path.Join(d.root, k))
+	b, err := d.read(d.path(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	if err == nil && d.sum {
+		return unsum(b)
+	}
+	return b, err
 }
 
 // Set the value of a key.
 func (d *DocDriver) Set(k string, v []byte) error {
-	return os.WriteFile(path.Join(d.root, k), v, 0644)
+	if d.shard {
+		if err := os.MkdirAll(path.Dir(d.path(k)), 0755); err != nil {
+			return err
+		}
+	}
+	if d.sum {
+		v = sum(v)
+	}
+	if err := os.WriteFile(d.path(k), v, 0644); err != nil {
+		return err
+	}
+	return d.sync(d.path(k))
 }
 
 // Del the value of a key.
 func (d *DocDriver) Del(k string) error {
-	return os.Remove(path.Join(d.root, k))
+	err := os.Remove(d.path(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	}
+	if err != nil {
+		return err
+	}
+	return d.sync(path.Dir(d.path(k)))
+}
+
+// Scan calls f for each key and its value, in lexical order of keys. Files are read ahead in the background, so f is
+// rarely left waiting on the disk.
+func (d *DocDriver) Scan(f func(k string, v []byte) error) error {
+	l, err := d.list()
+	if err != nil {
+		return err
+	}
+	type item struct {
+		k    string
+		v    []byte
+		err  error
+		done chan struct{}
+	}
+	q := make(chan *item, 64)
+	s := make(chan struct{}, 8)
+	c := make(chan struct{})
+	defer close(c)
+	go func() {
+		defer close(q)
+		for _, e := range l {
+			i := &item{k: e.Name(), done: make(chan struct{})}
+			select {
+			case q <- i:
+			case <-c:
+				return
+			}
+			s <- struct{}{}
+			go func() {
+				i.v, i.err = d.Get(i.k)
+				close(i.done)
+				<-s
+			}()
+		}
+	}()
+	for i := range q {
+		<-i.done
+		if errors.Is(i.err, ErrNotExist) {
+			continue
+		}
+		if i.err != nil {
+			return i.err
+		}
+		if err := f(i.k, i.v); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // LruDriver implemention. In computing, cache algorithms (also frequently called cache replacement algorithms or cache
@@ -96,42 +227,168 @@ func (d *DocDriver) Del(k string) error {
 // Least recently used (LRU), discards the least recently used items first. It has a fixed size(for limit memory usages)
 // and O(1) time lookup.
 type LruDriver struct {
-	data *lru.Lru[string, []byte]
+	data  *lru.Lru[string, []byte]
+	age   *lru.List[string, struct{}]
+	ages  map[string]*lru.Elem[string, struct{}]
+	hit   uint64
+	miss  uint64
+	evict uint64
 }
 
 // NewLruDriver returns a LruDriver.
 func NewLruDriver(size int) *LruDriver {
 	return &LruDriver{
 		data: lru.New[string, []byte](size, time.Hour*24),
+		age:  (&lru.List[string, struct{}]{}).Init(),
+		ages: map[string]*lru.Elem[string, struct{}]{},
 	}
 }
 
-// Get the value of a key.
+// remove drops an entry from the cache and from the set order. The caller must hold the lock.
+func (d *LruDriver) remove(e *lru.Elem[string, []byte]) {
+	delete(d.data.C, e.K)
+	d.data.List.Remove(e)
+	d.age.Remove(d.ages[e.K])
+	delete(d.ages, e.K)
+}
+
+// stamp restarts the time to live of an entry and moves it to the newest end of the set order. The caller must hold
+// the lock.
+func (d *LruDriver) stamp(e *lru.Elem[string, []byte]) {
+	e.U = time.Now()
+	if a, b := d.ages[e.K]; b {
+		a.U = e.U
+		d.age.Move(a, &d.age.Root)
+		return
+	}
+	d.ages[e.K] = d.age.Insert(&lru.Elem[string, struct{}]{K: e.K, U: e.U}, &d.age.Root)
+}
+
+// Get the value of a key. Expired entries are never returned, they are removed as soon as they are found.
 func (d *LruDriver) Get(k string) ([]byte, error) {
-	v, b := d.data.GetExists(k)
-	if b {
-		return v, nil
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	e, b := d.data.C[k]
+	if !b {
+		d.miss++
+		return nil, ErrNotExist
 	}
-	return nil, os.ErrNotExist
+	if time.Since(e.U) > d.data.E {
+		d.remove(e)
+		d.miss++
+		return nil, ErrNotExist
+	}
+	d.data.List.Move(e, &d.data.List.Root)
+	d.hit++
+	return e.V, nil
 }
 
-// Set the value of a key.
+// Set the value of a key. Entries are also kept in the order they were set, in which expired entries always come
+// first, so they are dropped before the least recently used entry is evicted, at an amortized O(1) cost.
 func (d *LruDriver) Set(k string, v []byte) error {
-	d.data.Set(k, v)
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	if e, b := d.data.C[k]; b {
+		d.data.List.Move(e, &d.data.List.Root)
+		e.V = v
+		d.stamp(e)
+		return nil
+	}
+	d.expire()
+	if d.data.List.Size >= d.data.Size && d.data.List.Size > 0 {
+		d.remove(d.data.List.Root.Prev)
+		d.evict++
+	}
+	e := d.data.List.Insert(&lru.Elem[string, []byte]{K: k, V: v}, &d.data.List.Root)
+	d.data.C[k] = e
+	d.stamp(e)
 	return nil
 }
 
 // Del the value of a key.
 func (d *LruDriver) Del(k string) error {
-	d.data.Del(k)
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	e, b := d.data.C[k]
+	if !b {
+		return ErrNotExist
+	}
+	d.remove(e)
+	if time.Since(e.U) > d.data.E {
+		return ErrNotExist
+	}
+	return nil
+}
+
+// Scan calls f for each key and its value, from the most recently used to the least. Expired entries are skipped.
+func (d *LruDriver) Scan(f func(k string, v []byte) error) error {
+	d.data.M.Lock()
+	l := make([]*lru.Elem[string, []byte], 0, d.data.List.Size)
+	for e := d.data.List.Root.Next; e != &d.data.List.Root; e = e.Next {
+		if time.Since(e.U) <= d.data.E {
+			l = append(l, &lru.Elem[string, []byte]{K: e.K, V: e.V})
+		}
+	}
+	d.data.M.Unlock()
+	for _, e := range l {
+		if err := f(e.K, e.V); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// expire removes all expired entries, the oldest ones of the set order. The caller must hold the lock.
+func (d *LruDriver) expire() {
+	for d.age.Size > 0 && time.Since(d.age.Root.Prev.U) > d.data.E {
+		d.remove(d.data.C[d.age.Root.Prev.K])
+	}
+}
+
+// Shrink discards the given fraction of entries, expired entries and the least recently used first. A ratio of 1 or
+// more empties the cache, a negative ratio only discards expired entries.
+func (d *LruDriver) Shrink(ratio float64) {
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	d.expire()
+	n := d.data.List.Size
+	if ratio < 1 {
+		n = int(float64(n) * ratio)
+	}
+	for ; n > 0; n-- {
+		d.remove(d.data.List.Root.Prev)
+	}
+}
+
+// ExpiringBefore returns the keys which will expire before t, the soonest first. Keys already expired are included.
+func (d *LruDriver) ExpiringBefore(t time.Time) ([]string, error) {
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	l := []*lru.Elem[string, []byte]{}
+	for e := d.data.List.Root.Next; e != &d.data.List.Root; e = e.Next {
+		if e.U.Add(d.data.E).Before(t) {
+			l = append(l, e)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].U.Before(l[j].U) })
+	r := make([]string, len(l))
+	for i, e := range l {
+		r[i] = e.K
+	}
+	return r, nil
+}
+
 // MapDriver is based on DocDriver and use LruDriver to provide caching at its
 // interface layer. The size of LruDriver is always 1024.
 type MapDriver struct {
-	doc *DocDriver
-	lru *LruDriver
+	doc   *DocDriver
+	lru   *LruDriver
+	rep   atomic.Bool
+	rpc   atomic.Uint64
+	m     *sync.Mutex
+	wb    *writeBack
+	bloom *bloom
+	neg   *lru.Lru[string, struct{}]
 }
 
 // NewMapDriver returns a MapDriver.
@@ -139,6 +396,7 @@ func NewMapDriver(root string) *MapDriver {
 	return &MapDriver{
 		doc: NewDocDriver(root),
 		lru: NewLruDriver(1024),
+		m:   &sync.Mutex{},
 	}
 }
 
@@ -148,11 +406,33 @@ func (d *MapDriver) Get(k string) ([]byte, error) {
 		buf []byte
 		err error
 	)
+	if d.wb != nil {
+		if e, b := d.wb.get(k); b {
+			if e.del {
+				return nil, ErrNotExist
+			}
+			return e.v, nil
+		}
+	}
 	buf, err = d.lru.Get(k)
 	if err == nil {
+		if d.rep.Load() {
+			d.repair(k, buf)
+		}
 		return buf, nil
 	}
+	if d.bloom != nil && !d.bloom.has(k) {
+		return nil, ErrNotExist
+	}
+	if d.neg != nil {
+		if _, b := d.neg.GetExists(k); b {
+			return nil, ErrNotExist
+		}
+	}
 	buf, err = d.doc.Get(k)
+	if errors.Is(err, ErrNotExist) && d.neg != nil {
+		d.neg.Set(k, struct{}{})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -160,11 +440,57 @@ func (d *MapDriver) Get(k string) ([]byte, error) {
 	return buf, err
 }
 
+// repair rewrites the file system copy of a key if it is missing or differs from the cached value. If the copy can't
+// be rewritten, the cached value is dropped so that the next read goes to the file system. Repairs are serialised with
+// writes, so that a concurrent Set is never overwritten with the value it replaced.
+func (d *MapDriver) repair(k string, v []byte) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if c, err := d.lru.Head(k); err != nil || c.Sum != crc32.ChecksumIEEE(v) {
+		return
+	}
+	buf, err := d.doc.Get(k)
+	if err == nil && bytes.Equal(buf, v) {
+		return
+	}
+	d.rpc.Add(1)
+	if d.doc.Set(k, v) != nil {
+		d.lru.Del(k)
+	}
+}
+
+// Repair enables or disables read-repair. When enabled, every cache hit is verified against the file system.
+func (d *MapDriver) Repair(b bool) {
+	d.rep.Store(b)
+}
+
+// Repaired returns the number of inconsistencies detected by read-repair.
+func (d *MapDriver) Repaired() uint64 {
+	return d.rpc.Load()
+}
+
+// Shrink discards the given fraction of cached entries. Values stay on the file system.
+func (d *MapDriver) Shrink(ratio float64) {
+	d.lru.Shrink(ratio)
+}
+
 // Set the value of a key.
 func (d *MapDriver) Set(k string, v []byte) error {
+	if d.bloom != nil {
+		d.bloom.add(k)
+	}
+	if d.neg != nil {
+		d.neg.Del(k)
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
 	if err := d.lru.Set(k, v); err != nil {
 		return err
 	}
+	if d.wb != nil {
+		d.wb.put(k, &dirty{v: v})
+		return nil
+	}
 	if err := d.doc.Set(k, v); err != nil {
 		return err
 	}
@@ -173,7 +499,19 @@ func (d *MapDriver) Set(k string, v []byte) error {
 
 // Del the value of a key.
 func (d *MapDriver) Del(k string) error {
-	if err := d.lru.Del(k); err != nil {
+	if d.wb != nil {
+		if _, err := d.Get(k); err != nil {
+			return err
+		}
+		d.m.Lock()
+		defer d.m.Unlock()
+		d.lru.Del(k)
+		d.wb.put(k, &dirty{del: true})
+		return nil
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err := d.lru.Del(k); err != nil && !errors.Is(err, ErrNotExist) {
 		return err
 	}
 	if err := d.doc.Del(k); err != nil {
@@ -182,22 +520,48 @@ func (d *MapDriver) Del(k string) error {
 	return nil
 }
 
-// Client is a actuator of the given drive. Do not worry, Is's concurrency-safety.
+// Scan calls f for each key and its value. The values are read from the file system, after flushing pending writes.
+func (d *MapDriver) Scan(f func(k string, v []byte) error) error {
+	if err := d.Flush(); err != nil {
+		return err
+	}
+	return d.doc.Scan(f)
+}
+
+// Client is a actuator of the given drive. Do not worry, Is's concurrency-safety. Reads share a lock, so concurrent
+// gets scale with cores, while writes are exclusive.
 type Client struct {
-	driver Driver
-	log    int
-	m      *sync.Mutex
+	driver  Driver
+	log     int
+	m       *sync.RWMutex
+	onset   []func(k string, v []byte) error
+	after   []func(k string, v []byte)
+	afterd  []func(k string)
+	codec   Codec
+	codecs  map[reflect.Type]Codec
+	track   *lru.Lru[string, *Access]
+	tm      *sync.Mutex
+	aliases bool
+	tags    bool
+	wait    map[string]chan struct{}
+	wm      *sync.Mutex
+	maxk    int
+	maxv    int
+	flight  map[string]*flight
+	fm      *sync.Mutex
 }
 
 // NewClient returns a Client.
 func NewClient(driver Driver) *Client {
-	return &Client{driver: driver, log: 1, m: &sync.Mutex{}}
+	return &Client{driver: driver, log: 1, m: &sync.RWMutex{}, codec: JsonCodec{}, codecs: map[reflect.Type]Codec{}, tm: &sync.Mutex{}, wait: map[string]chan struct{}{}, wm: &sync.Mutex{}, flight: map[string]*flight{}, fm: &sync.Mutex{}}
 }
 
-// Get the value of a key.
+// Get the value of a key. If the key is an alias, the value of its target is returned.
 func (e *Client) Get(k string) ([]byte, error) {
-	e.m.Lock()
-	defer e.m.Unlock()
+	e.m.RLock()
+	defer e.m.RUnlock()
+	k = e.resolve(k)
+	e.access(k)
 	return e.driver.Get(k)
 }
 
@@ -205,10 +569,79 @@ func (e *Client) Get(k string) ([]byte, error) {
 func (e *Client) Set(k string, v []byte) error {
 	e.m.Lock()
 	defer e.m.Unlock()
+	return e.set(k, v)
+}
+
+// set the value of a key. The caller must hold the lock.
+func (e *Client) set(k string, v []byte) error {
+	if (e.maxk > 0 && len(k) > e.maxk) || (e.maxv > 0 && len(v) > e.maxv) {
+		return ErrTooLarge
+	}
+	for _, f := range e.onset {
+		if err := f(k, v); err != nil {
+			return err
+		}
+	}
 	if e.log != 0 {
 		log.Println("acdb: set", k, string(v))
 	}
-	return e.driver.Set(k, v)
+	e.access(k)
+	if err := e.driver.Set(k, v); err != nil {
+		return err
+	}
+	e.notify(k)
+	for _, f := range e.after {
+		f(k, v)
+	}
+	return nil
+}
+
+// Limit rejects keys longer than key bytes and values larger than value bytes with ErrTooLarge, before they reach the
+// driver. Zero means no limit.
+func (e *Client) Limit(key int, value int) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.maxk = key
+	e.maxv = value
+}
+
+// SetNone sets the value of a key only if the key does not exist. Otherwise ErrHasExist is returned.
+func (e *Client) SetNone(k string, v []byte) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	_, err := e.driver.Get(k)
+	if err == nil {
+		return ErrHasExist
+	}
+	if !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	return e.set(k, v)
+}
+
+// OnSet registers a validator which is called before every set. If any validator returns an error, the set is
+// rejected and the error is returned to the caller.
+func (e *Client) OnSet(f func(k string, v []byte) error) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.onset = append(e.onset, f)
+}
+
+// AfterSet registers an observer which is called after every successful set, for example to invalidate a secondary
+// cache. Observers run synchronously while the client is locked, so they must not call the client. Values streamed
+// with SetReader are passed as nil.
+func (e *Client) AfterSet(f func(k string, v []byte)) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.after = append(e.after, f)
+}
+
+// AfterDel registers an observer which is called after every successful del. Observers run synchronously while the
+// client is locked, so they must not call the client.
+func (e *Client) AfterDel(f func(k string)) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.afterd = append(e.afterd, f)
 }
 
 // GetDecode get the decoded value of a key.
@@ -217,12 +650,12 @@ func (e *Client) GetDecode(k string, v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(b, v)
+	return e.codecOf(v).Unmarshal(b, v)
 }
 
 // SetEncode set the encoded value of a key.
 func (e *Client) SetEncode(k string, v interface{}) error {
-	b, err := json.Marshal(v)
+	b, err := e.codecOf(v).Marshal(v)
 	if err != nil {
 		return err
 	}
@@ -268,7 +701,104 @@ func (e *Client) GetString(k string) (string, error) {
 func (e *Client) Del(k string) error {
 	e.m.Lock()
 	defer e.m.Unlock()
-	return e.driver.Del(k)
+	return e.del(k)
+}
+
+// del the value of a key. The caller must hold the lock.
+func (e *Client) del(k string) error {
+	if err := e.driver.Del(k); err != nil {
+		return err
+	}
+	if e.tags {
+		e.untag(k)
+	}
+	e.notify(k)
+	for _, f := range e.afterd {
+		f(k)
+	}
+	return nil
+}
+
+// GetSet sets the encoded value of a key and decodes its previous value into old, atomically. If the key did not
+// exist, old is left untouched.
+func (e *Client) GetSet(k string, v interface{}, old interface{}) error {
+	b, err := e.codecOf(v).Marshal(v)
+	if err != nil {
+		return err
+	}
+	c := e.codecOf(old)
+	e.m.Lock()
+	defer e.m.Unlock()
+	p, err := e.driver.Get(k)
+	if err == nil {
+		if err := c.Unmarshal(p, old); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	return e.set(k, b)
+}
+
+// GetDel decodes the value of a key into v and deletes the key, atomically.
+func (e *Client) GetDel(k string, v interface{}) error {
+	c := e.codecOf(v)
+	e.m.Lock()
+	defer e.m.Unlock()
+	b, err := e.driver.Get(k)
+	if err != nil {
+		return err
+	}
+	if err := c.Unmarshal(b, v); err != nil {
+		return err
+	}
+	return e.del(k)
+}
+
+// Swap exchanges the values of two keys atomically, no reader ever sees both keys holding the same value. Both keys
+// must exist.
+func (e *Client) Swap(a string, b string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	va, err := e.driver.Get(a)
+	if err != nil {
+		return err
+	}
+	vb, err := e.driver.Get(b)
+	if err != nil {
+		return err
+	}
+	if err := e.set(a, vb); err != nil {
+		return err
+	}
+	if err := e.set(b, va); err != nil {
+		e.driver.Set(a, va)
+		return err
+	}
+	return nil
+}
+
+// Scan calls f for each key and its value. The driver must implement Scanner. The client is locked during the scan,
+// so f must not call the client.
+func (e *Client) Scan(f func(k string, v []byte) error) error {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	s, b := e.driver.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(f)
+}
+
+// ExpiringBefore returns the keys which will expire before t. The driver must implement Expirer.
+func (e *Client) ExpiringBefore(t time.Time) ([]string, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	x, b := e.driver.(Expirer)
+	if !b {
+		return nil, ErrUnsupported
+	}
+	return x.ExpiringBefore(t)
 }
 
 // Has determine if a key exists.
@@ -288,6 +818,30 @@ func (e *Client) Log(l int) {
 	e.log = l
 }
 
+// Driver returns the driver of the client. Using it directly bypasses the lock, validators, limits and notifications
+// of the client.
+func (e *Client) Driver() Driver {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	return e.driver
+}
+
+// SwapDriver replaces the driver of the client, for example to move from a MemDriver to a MapDriver at runtime or to
+// wrap the driver after construction. If migrate is true, all keys of the old driver are copied to the new one first,
+// the old driver must implement Scanner, and the old driver is kept if copying fails. No other operation runs on the
+// client meanwhile.
+func (e *Client) SwapDriver(d Driver, migrate bool) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if migrate {
+		if _, err := Migrate(e.driver, d, nil); err != nil {
+			return err
+		}
+	}
+	e.driver = d
+	return nil
+}
+
 // Mem returns a concurrency-safety Client with MemDriver.
 func Mem() *Client { return NewClient(NewMemDriver()) }
 
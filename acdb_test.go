@@ -0,0 +1,641 @@
+package acdb
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMapDriverRepairConcurrent(t *testing.T) {
+	root := t.TempDir()
+	d := NewMapDriver(root)
+	d.Repair(true)
+	c := NewClient(d)
+	c.Log(0)
+	for i := 0; i < 16; i++ {
+		if err := c.Set(strconv.Itoa(i), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(path.Join(root, "0")); err != nil {
+		t.Fatal(err)
+	}
+	w := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		w.Add(1)
+		go func() {
+			defer w.Done()
+			for j := 0; j < 64; j++ {
+				if _, err := c.Get(strconv.Itoa(j % 16)); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	w.Wait()
+	if d.Repaired() == 0 {
+		t.Fatal("missing file was not repaired")
+	}
+	if _, err := os.Stat(path.Join(root, "0")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignDriverMovedValue(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMemDriver()
+	d := NewSignDriver(m)
+	d.Trust("cfg/", pub)
+	if err := d.SetSigned("cfg/old", []byte("old"), priv); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.Get("cfg/old"); err != nil || string(v) != "old" {
+		t.Fatal(v, err)
+	}
+	v, _ := m.Get("cfg/old")
+	s, _ := m.Get("cfg/old.sig")
+	m.Set("cfg/active", v)
+	m.Set("cfg/active.sig", s)
+	if _, err := d.Get("cfg/active"); !errors.Is(err, ErrSignature) {
+		t.Fatal(err)
+	}
+}
+
+func TestClientRenameSelf(t *testing.T) {
+	for _, c := range []*Client{Mem(), Doc(t.TempDir())} {
+		c.Log(0)
+		if err := c.Set("a", []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Tag("a", "t"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Rename("a", "a"); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := c.Get("a"); err != nil || string(v) != "v" {
+			t.Fatal(v, err)
+		}
+		if l, err := c.Tagged("t"); err != nil || len(l) != 1 {
+			t.Fatal(l, err)
+		}
+		if err := c.Rename("b", "b"); !errors.Is(err, ErrNotExist) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestClientLockKeepsData(t *testing.T) {
+	c := Mem()
+	c.Log(0)
+	if err := c.Set("user", []byte(`{"name":"bob"}`)); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := c.Lock("user", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Lock("user", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatal(err)
+	}
+	unlock()
+	if v, err := c.Get("user"); err != nil || string(v) != `{"name":"bob"}` {
+		t.Fatal(string(v), err)
+	}
+}
+
+func TestDumpLruKeepsRecency(t *testing.T) {
+	d := NewLruDriver(3)
+	for _, k := range []string{"c", "b", "a"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := d.Stats().Hit
+	w := bytes.Buffer{}
+	if err := Dump(d, &w); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(w.String(), `{"k":"a"`) {
+		t.Fatal(w.String())
+	}
+	if d.Stats().Hit != h {
+		t.Fatal("dump counted cache hits")
+	}
+	if err := d.Set("d", []byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Get("c"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("least recently used key was kept")
+	}
+	if _, err := d.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDumpDocSorted(t *testing.T) {
+	d := NewDocDriver(t.TempDir())
+	for _, k := range []string{"b", "c", "a"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := bytes.Buffer{}
+	if err := Dump(d, &w); err != nil {
+		t.Fatal(err)
+	}
+	l := strings.Split(strings.TrimSpace(w.String()), "\n")
+	if len(l) != 3 || !strings.Contains(l[0], `"a"`) || !strings.Contains(l[2], `"c"`) {
+		t.Fatal(l)
+	}
+}
+
+func TestLruDriverEvictsExpiredFirst(t *testing.T) {
+	d := NewLruDriver(3)
+	d.data.E = 100 * time.Millisecond
+	for _, k := range []string{"a", "b"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := d.Set("c", []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	// a and b become the most recently used, then expire before c.
+	for _, k := range []string{"a", "b"} {
+		if _, err := d.Get(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	for _, k := range []string{"d", "e"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.Get("c"); err != nil {
+		t.Fatal("live entry evicted before expired ones")
+	}
+	if d.Stats().Evict != 0 {
+		t.Fatal(d.Stats().Evict)
+	}
+}
+
+func TestLruDriverShrink(t *testing.T) {
+	d := NewLruDriver(8)
+	for i := 0; i < 4; i++ {
+		if err := d.Set(strconv.Itoa(i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.Shrink(-1)
+	if d.Stats().Keys != 4 {
+		t.Fatal(d.Stats().Keys)
+	}
+	d.Shrink(0.5)
+	if _, err := d.Get("0"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("least recently used entry kept")
+	}
+	if _, err := d.Get("3"); err != nil {
+		t.Fatal(err)
+	}
+	d.Shrink(2)
+	if d.Stats().Keys != 0 {
+		t.Fatal(d.Stats().Keys)
+	}
+}
+
+func TestMapDriverBloomRate(t *testing.T) {
+	for _, p := range []float64{0, -1, 1, 2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("rate accepted", p)
+				}
+			}()
+			NewMapDriver(t.TempDir()).Bloom(16, p)
+		}()
+	}
+	d := NewMapDriver(t.TempDir())
+	if err := d.Bloom(16, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeriesSpan(t *testing.T) {
+	c := Mem()
+	c.Log(0)
+	for _, d := range []time.Duration{0, 500 * time.Millisecond, 1500 * time.Millisecond} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("span accepted", d)
+				}
+			}()
+			c.Series("s").Span(d)
+		}()
+	}
+	s := c.Series("s").Span(time.Second).Size(2)
+	t0 := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		if err := s.Append(t0.Add(time.Duration(i)*300*time.Millisecond), float64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := s.Range(t0, t0.Add(time.Hour))
+	if err != nil || len(p) != 10 || p[9].V != 9 {
+		t.Fatal(p, err)
+	}
+}
+
+func TestMetaDriverExistingStore(t *testing.T) {
+	m := NewMemDriver()
+	if err := m.Set("a", []byte("plain value of more than 28 bytes")); err != nil {
+		t.Fatal(err)
+	}
+	d := NewMetaDriver(m)
+	if v, err := d.Get("a"); err != nil || string(v) != "plain value of more than 28 bytes" {
+		t.Fatal(string(v), err)
+	}
+	if _, err := d.Meta("a"); !errors.Is(err, ErrNoMeta) {
+		t.Fatal(err)
+	}
+	if err := d.Set("a", []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.Get("a"); err != nil || string(v) != "new" {
+		t.Fatal(string(v), err)
+	}
+	if r, err := d.Meta("a"); err != nil || r.Version != 1 {
+		t.Fatal(r, err)
+	}
+}
+
+// countDriver counts the calls of Get.
+type countDriver struct {
+	Driver
+	gets int
+}
+
+func (d *countDriver) Get(k string) ([]byte, error) {
+	d.gets++
+	return d.Driver.Get(k)
+}
+
+func TestClientTags(t *testing.T) {
+	m := NewMemDriver()
+	d := &countDriver{Driver: m}
+	c := NewClient(d)
+	c.Log(0)
+	for _, k := range []string{"a", "b"} {
+		if err := c.Set(k, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Del("b"); err != nil || d.gets != 0 {
+		t.Fatal("untagged delete read the index", err)
+	}
+	if err := c.Tag("a", "t"); err != nil {
+		t.Fatal(err)
+	}
+	o := NewClient(m)
+	o.Log(0)
+	if err := o.Del("a"); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := c.Tagged("t"); err != nil || len(l) != 0 {
+		t.Fatal(l, err)
+	}
+	if _, err := m.Get(keyTagPrefix + "a"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("stale index kept")
+	}
+}
+
+func TestSnapDriverWrites(t *testing.T) {
+	name := path.Join(t.TempDir(), "snap")
+	d := NewSnapDriver(NewMemDriver(), name, 0, 2)
+	for _, k := range []string{"a", "b"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; ; i++ {
+		if _, err := os.Stat(name); err == nil {
+			break
+		}
+		if i == 100 {
+			t.Fatal("no snapshot after enough writes")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := d.Set("c", []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	e := NewSnapDriver(NewMemDriver(), name, 0, 0)
+	defer e.Close()
+	if v, err := e.Get("c"); err != nil || string(v) != "c" {
+		t.Fatal(string(v), err)
+	}
+	if d.Err() != nil {
+		t.Fatal(d.Err())
+	}
+}
+
+func TestDocDriverHead(t *testing.T) {
+	v := []byte("a value to describe")
+	for _, sum := range []bool{false, true} {
+		d := NewDocDriver(t.TempDir())
+		d.Checksum(sum)
+		if err := d.Set("a", v); err != nil {
+			t.Fatal(err)
+		}
+		h, err := d.Head("a")
+		if err != nil || h.Size != int64(len(v)) || h.Sum != crc32.ChecksumIEEE(v) {
+			t.Fatal(h, err)
+		}
+		if _, err := d.Head("b"); !errors.Is(err, ErrNotExist) {
+			t.Fatal(err)
+		}
+	}
+	d := NewMapDriver(t.TempDir())
+	if err := d.Set("a", v); err != nil {
+		t.Fatal(err)
+	}
+	if h, err := NewClient(d).Head("a"); err != nil || h.Size != int64(len(v)) || h.Sum != crc32.ChecksumIEEE(v) {
+		t.Fatal(h, err)
+	}
+}
+
+func TestGitDriverRejectsGitDir(t *testing.T) {
+	d := NewGitDriver(t.TempDir())
+	for _, k := range []string{".git/config", ".git", "a/../.git/config", "../x"} {
+		if err := d.Set(k, []byte("[core]\n\tfsmonitor = true\n")); err == nil {
+			t.Fatal("key accepted", k)
+		}
+		if err := d.Del(k); err == nil || errors.Is(err, ErrNotExist) {
+			t.Fatal("key accepted", k)
+		}
+	}
+	if err := d.Set(".gitignore", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArcDriverScanResistance(t *testing.T) {
+	d := NewArcDriver(4)
+	for _, k := range []string{"a", "b"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Get(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 16; i++ {
+		if err := d.Set("x"+strconv.Itoa(i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, k := range []string{"a", "b"} {
+		if v, err := d.Get(k); err != nil || string(v) != k {
+			t.Fatal("frequently used key evicted by a scan", k)
+		}
+	}
+	if _, err := d.Get("x0"); !errors.Is(err, ErrNotExist) {
+		t.Fatal(err)
+	}
+	if d.t1.Len()+d.t2.Len() > 4 || d.t1.Len()+d.b1.Len() > 4 || len(d.data) > 8 {
+		t.Fatal(d.t1.Len(), d.t2.Len(), d.b1.Len(), d.b2.Len())
+	}
+}
+
+func TestArcDriverGhostAdapts(t *testing.T) {
+	d := NewArcDriver(4)
+	for _, k := range []string{"a", "b", "c"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.Get("c"); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"d", "e"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The cache was full, so a was evicted from the recent list into its ghost list.
+	if _, err := d.Get("a"); !errors.Is(err, ErrNotExist) {
+		t.Fatal(err)
+	}
+	if e := d.data["a"]; e == nil || e.l != d.b1 {
+		t.Fatal("evicted key not remembered")
+	}
+	p := d.p
+	if err := d.Set("a", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if d.p <= p {
+		t.Fatal("recent ghost hit did not grow the recent target", d.p)
+	}
+	if e := d.data["a"]; e.l != d.t2 {
+		t.Fatal("ghost hit not promoted to the frequent list")
+	}
+	if err := d.Del("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, b := d.data["a"]; b {
+		t.Fatal("deleted key kept")
+	}
+	if d.t1.Len()+d.t2.Len() > 4 {
+		t.Fatal(d.t1.Len(), d.t2.Len())
+	}
+}
+
+func TestLfuDriverEvictsLeastFrequent(t *testing.T) {
+	d := NewLfuDriver(3)
+	for _, k := range []string{"a", "b", "c"} {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, k := range []string{"a", "a", "b", "c", "c"} {
+		if _, err := d.Get(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The least frequency is now 2, held by b alone. Deleting it must not leave a stale least.
+	if err := d.Del("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("d", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("e", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Get("d"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("least frequent key kept")
+	}
+	for _, k := range []string{"a", "c", "e"} {
+		if _, err := d.Get(k); err != nil {
+			t.Fatal(k, err)
+		}
+	}
+	// Ties are broken in least recently used order.
+	if err := d.Set("f", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Get("e"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("least recently used of the least frequent kept")
+	}
+	for _, k := range []string{"a", "c", "f"} {
+		if _, err := d.Get(k); err != nil {
+			t.Fatal(k, err)
+		}
+	}
+	for _, k := range []string{"a", "c", "f"} {
+		if err := d.Del(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(d.data) != 0 || len(d.freq) != 0 {
+		t.Fatal(len(d.data), len(d.freq))
+	}
+}
+
+func TestMapDriverWriteBackConcurrent(t *testing.T) {
+	root := t.TempDir()
+	d := NewMapDriver(root)
+	d.WriteBack(time.Millisecond)
+	w := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		w.Add(1)
+		go func(i int) {
+			defer w.Done()
+			k := strconv.Itoa(i)
+			for j := 0; j < 200; j++ {
+				if j%3 == 2 {
+					if err := d.Del(k); err != nil && !errors.Is(err, ErrNotExist) {
+						t.Error(err)
+					}
+					continue
+				}
+				v := []byte(strconv.Itoa(j))
+				if err := d.Set(k, v); err != nil {
+					t.Error(err)
+				}
+				if r, err := d.Get(k); err != nil || string(r) != string(v) {
+					t.Error("pending write not read back", k, string(r), err)
+				}
+			}
+		}(i)
+	}
+	w.Wait()
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The last write of each key is a set of 199, which must be on the file system after Close.
+	f := NewDocDriver(root)
+	for i := 0; i < 8; i++ {
+		if v, err := f.Get(strconv.Itoa(i)); err != nil || string(v) != "199" {
+			t.Fatal(i, string(v), err)
+		}
+	}
+	if err := d.Del("0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Get("0"); !errors.Is(err, ErrNotExist) {
+		t.Fatal("flushed del left the file", err)
+	}
+}
+
+func TestDocDriverShard(t *testing.T) {
+	root := t.TempDir()
+	d := NewDocDriver(root)
+	keys := []string{"a", "ab", "0f", "key"}
+	for _, k := range keys {
+		if err := d.Set(k, []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Shard(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(root, ".shard")); !os.IsNotExist(err) {
+		t.Fatal("staging tree left behind", err)
+	}
+	for _, k := range keys {
+		if i, err := os.Stat(path.Join(root, k)); err == nil && !i.IsDir() {
+			t.Fatal("flat file left behind", k)
+		}
+		if v, err := d.Get(k); err != nil || string(v) != k {
+			t.Fatal(k, string(v), err)
+		}
+	}
+	// A driver opened again on the root finds every key once sharded.
+	e := NewDocDriver(root)
+	if err := e.Shard(); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	if err := e.Scan(func(k string, v []byte) error {
+		n++
+		return nil
+	}); err != nil || n != len(keys) {
+		t.Fatal(n, err)
+	}
+}
+
+func TestDocDriverShardResume(t *testing.T) {
+	root := t.TempDir()
+	// A migration which failed after removing the flat files left the keys in the staging tree.
+	s := &DocDriver{root: path.Join(root, ".shard"), shard: true}
+	for _, k := range []string{"a", "b"} {
+		if err := os.MkdirAll(path.Dir(s.path(k)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(s.path(k), []byte(k), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := NewDocDriver(root)
+	if err := d.Shard(); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b"} {
+		if v, err := d.Get(k); err != nil || string(v) != k {
+			t.Fatal(k, string(v), err)
+		}
+	}
+	if _, err := os.Stat(s.root); !os.IsNotExist(err) {
+		t.Fatal("staging tree left behind", err)
+	}
+}
@@ -0,0 +1,59 @@
+package acdb
+
+import (
+	"errors"
+)
+
+// aliasPrefix is the key prefix under which aliases are stored in the driver.
+const aliasPrefix = "acdb.alias."
+
+// Alias makes reads of key alias resolve to key target, so consumers can always read a stable name while publishers
+// version their values. Repointing an existing alias is atomic. Aliases are stored in the driver under the key
+// "acdb.alias." + alias, so they are seen by every client of the same store and survive restarts, but clients only
+// resolve them once enabled with Aliases, which Alias does for its own client. Aliases apply to Get, GetReader, Touch,
+// Head, Meta, GetWait and the hash and set readers; writes to the alias name are not redirected.
+func (e *Client) Alias(alias string, target string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.aliases = true
+	if err := e.driver.Set(aliasPrefix+alias, []byte(target)); err != nil {
+		return err
+	}
+	e.notify(alias)
+	return nil
+}
+
+// Unalias removes an alias.
+func (e *Client) Unalias(alias string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	err := e.driver.Del(aliasPrefix + alias)
+	if errors.Is(err, ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.notify(alias)
+	return nil
+}
+
+// Aliases enables or disables the resolution of aliases on reads. Resolution costs one more driver lookup per read, so
+// it is disabled by default.
+func (e *Client) Aliases(b bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.aliases = b
+}
+
+// resolve returns the target of a key if it is an alias, or the key itself. The caller must hold the lock.
+func (e *Client) resolve(k string) string {
+	if !e.aliases {
+		return k
+	}
+	t, err := e.driver.Get(aliasPrefix + k)
+	if err != nil {
+		return k
+	}
+	return string(t)
+}
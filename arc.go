@@ -0,0 +1,173 @@
+package acdb
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/godump/doa"
+)
+
+// ArcDriver implemention. Adaptive replacement cache (ARC), keeps track of both frequently used and recently used
+// items, plus a recent eviction history for both. It constantly balances between the two, so a scan over many keys
+// used only once can't evict the frequently used ones.
+type ArcDriver struct {
+	data map[string]*arcEntry
+	t1   *list.List
+	t2   *list.List
+	b1   *list.List
+	b2   *list.List
+	p    int
+	size int
+	m    *sync.Mutex
+}
+
+type arcEntry struct {
+	k string
+	v []byte
+	l *list.List
+	e *list.Element
+}
+
+// NewArcDriver returns a ArcDriver. The size must be at least 1.
+func NewArcDriver(size int) *ArcDriver {
+	doa.Doa(size >= 1)
+	return &ArcDriver{
+		data: map[string]*arcEntry{},
+		t1:   list.New(),
+		t2:   list.New(),
+		b1:   list.New(),
+		b2:   list.New(),
+		size: size,
+		m:    &sync.Mutex{},
+	}
+}
+
+// move moves an entry to the front of list l.
+func (d *ArcDriver) move(e *arcEntry, l *list.List) {
+	if e.l != nil {
+		e.l.Remove(e.e)
+	}
+	e.l = l
+	e.e = l.PushFront(e)
+}
+
+// drop removes the least recently used entry of list l.
+func (d *ArcDriver) drop(l *list.List) {
+	e := l.Remove(l.Back()).(*arcEntry)
+	delete(d.data, e.k)
+}
+
+// replace moves the least recently used entry of t1 or t2 into its ghost list.
+func (d *ArcDriver) replace(inb2 bool) {
+	if d.t1.Len() > 0 && (d.t1.Len() > d.p || (inb2 && d.t1.Len() == d.p)) {
+		e := d.t1.Back().Value.(*arcEntry)
+		e.v = nil
+		d.move(e, d.b1)
+		return
+	}
+	if d.t2.Len() > 0 {
+		e := d.t2.Back().Value.(*arcEntry)
+		e.v = nil
+		d.move(e, d.b2)
+	}
+}
+
+// Get the value of a key.
+func (d *ArcDriver) Get(k string) ([]byte, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, b := d.data[k]
+	if !b || (e.l != d.t1 && e.l != d.t2) {
+		return nil, ErrNotExist
+	}
+	d.move(e, d.t2)
+	return e.v, nil
+}
+
+// Set the value of a key.
+func (d *ArcDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, b := d.data[k]
+	switch {
+	case b && (e.l == d.t1 || e.l == d.t2):
+	case b && e.l == d.b1:
+		d.p += 1
+		if n := d.b2.Len() / d.b1.Len(); n > 1 {
+			d.p += n - 1
+		}
+		if d.p > d.size {
+			d.p = d.size
+		}
+		d.replace(false)
+	case b && e.l == d.b2:
+		d.p -= 1
+		if n := d.b1.Len() / d.b2.Len(); n > 1 {
+			d.p -= n - 1
+		}
+		if d.p < 0 {
+			d.p = 0
+		}
+		d.replace(true)
+	default:
+		l1 := d.t1.Len() + d.b1.Len()
+		l2 := d.t2.Len() + d.b2.Len()
+		if l1 >= d.size {
+			if d.t1.Len() < d.size {
+				d.drop(d.b1)
+				d.replace(false)
+			} else {
+				d.drop(d.t1)
+			}
+		} else if l1+l2 >= d.size {
+			if l1+l2 >= 2*d.size {
+				d.drop(d.b2)
+			}
+			d.replace(false)
+		}
+		e = &arcEntry{k: k, v: v}
+		d.data[k] = e
+		d.move(e, d.t1)
+		return nil
+	}
+	e.v = v
+	d.move(e, d.t2)
+	return nil
+}
+
+// Del the value of a key.
+func (d *ArcDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, b := d.data[k]
+	if !b {
+		return ErrNotExist
+	}
+	e.l.Remove(e.e)
+	delete(d.data, k)
+	if e.l != d.t1 && e.l != d.t2 {
+		return ErrNotExist
+	}
+	return nil
+}
+
+// Scan calls f for each key and its value.
+func (d *ArcDriver) Scan(f func(k string, v []byte) error) error {
+	d.m.Lock()
+	l := make([]arcEntry, 0, d.t1.Len()+d.t2.Len())
+	for _, e := range d.data {
+		if e.l == d.t1 || e.l == d.t2 {
+			l = append(l, arcEntry{k: e.k, v: e.v})
+		}
+	}
+	d.m.Unlock()
+	for _, e := range l {
+		if err := f(e.k, e.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Arc returns a concurrency-safety Client with ArcDriver.
+func Arc(size int) *Client { return NewClient(NewArcDriver(size)) }
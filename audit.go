@@ -0,0 +1,153 @@
+package acdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/godump/doa"
+)
+
+// AuditRecord is a line of the audit log.
+type AuditRecord struct {
+	T    time.Time `json:"t"`
+	Op   string    `json:"op"`
+	K    string    `json:"k"`
+	Size int       `json:"size"`
+	// V is the value written, unless values are hashed.
+	V []byte `json:"v,omitempty"`
+	// Sum is the hex SHA-256 of the value written, if values are hashed.
+	Sum string `json:"sum,omitempty"`
+}
+
+// AuditDriver wraps a driver and appends a json line describing every successful Set and Del to a log file. The file
+// is rotated like logrotate does: when it exceeds a size, it is renamed to name.1, name.1 to name.2 and so on.
+type AuditDriver struct {
+	inner Driver
+	name  string
+	max   int64
+	keep  int
+	hash  bool
+	f     *os.File
+	n     int64
+	m     *sync.Mutex
+}
+
+// NewAuditDriver returns an AuditDriver logging to the file name. The file is rotated when it exceeds max bytes, and
+// keep rotated files are kept. A max of zero or less disables rotation.
+func NewAuditDriver(inner Driver, name string, max int64, keep int) *AuditDriver {
+	d := &AuditDriver{inner: inner, name: name, max: max, keep: keep, m: &sync.Mutex{}}
+	doa.Nil(d.open())
+	return d
+}
+
+// open opens the log file for appending. The caller must hold the lock.
+func (d *AuditDriver) open() error {
+	f, err := os.OpenFile(d.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	i, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	d.f = f
+	d.n = i.Size()
+	return nil
+}
+
+// rotate renames the log files and opens a new one. The caller must hold the lock.
+func (d *AuditDriver) rotate() error {
+	if err := d.f.Close(); err != nil {
+		return err
+	}
+	for i := d.keep - 1; i >= 1; i-- {
+		err := os.Rename(d.name+"."+strconv.Itoa(i), d.name+"."+strconv.Itoa(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if d.keep > 0 {
+		if err := os.Rename(d.name, d.name+".1"); err != nil {
+			return err
+		}
+	} else {
+		if err := os.Remove(d.name); err != nil {
+			return err
+		}
+	}
+	return d.open()
+}
+
+// Hash makes the log record the SHA-256 of values instead of the values themselves, keeping it small and free of
+// sensitive data. It must be called before the driver is used.
+func (d *AuditDriver) Hash(b bool) {
+	d.hash = b
+}
+
+// audit appends a record to the log.
+func (d *AuditDriver) audit(op string, k string, v []byte) error {
+	r := AuditRecord{T: time.Now(), Op: op, K: k, Size: len(v)}
+	if op == "set" && d.hash {
+		s := sha256.Sum256(v)
+		r.Sum = hex.EncodeToString(s[:])
+	} else {
+		r.V = v
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.max > 0 && d.n > 0 && d.n+int64(len(b))+1 > d.max {
+		if err := d.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := d.f.Write(append(b, '\n'))
+	d.n += int64(n)
+	return err
+}
+
+// Get the value of a key.
+func (d *AuditDriver) Get(k string) ([]byte, error) {
+	return d.inner.Get(k)
+}
+
+// Set the value of a key.
+func (d *AuditDriver) Set(k string, v []byte) error {
+	if err := d.inner.Set(k, v); err != nil {
+		return err
+	}
+	return d.audit("set", k, v)
+}
+
+// Del the value of a key.
+func (d *AuditDriver) Del(k string) error {
+	if err := d.inner.Del(k); err != nil {
+		return err
+	}
+	return d.audit("del", k, nil)
+}
+
+// Scan calls f for each key and its value. The inner driver must implement Scanner.
+func (d *AuditDriver) Scan(f func(k string, v []byte) error) error {
+	s, b := d.inner.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(f)
+}
+
+// Close closes the log file.
+func (d *AuditDriver) Close() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.f.Close()
+}
@@ -0,0 +1,103 @@
+package acdb
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BenchOption controls the workload generated by Bench.
+type BenchOption struct {
+	// Workers is the number of concurrent goroutines.
+	Workers int
+	// Ops is the total number of operations.
+	Ops int
+	// Read is the fraction of operations which are gets, the others are sets.
+	Read float64
+	// Keys is the number of distinct keys.
+	Keys int
+	// Size is the size of each value in bytes.
+	Size int
+}
+
+// BenchResult holds the throughput and latency percentiles measured by Bench.
+type BenchResult struct {
+	Ops     int
+	Elapsed time.Duration
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// Throughput returns the number of operations per second.
+func (r BenchResult) Throughput() float64 {
+	return float64(r.Ops) / r.Elapsed.Seconds()
+}
+
+// Bench drives a mix of gets and sets against a driver and measures it. The keys bench0 to benchN are overwritten,
+// they are all set once before measuring. A Client is itself a Driver, so it can be measured too.
+func Bench(d Driver, opt BenchOption) (BenchResult, error) {
+	if opt.Workers <= 0 {
+		opt.Workers = 1
+	}
+	if opt.Keys <= 0 {
+		opt.Keys = 1
+	}
+	v := make([]byte, opt.Size)
+	rand.New(rand.NewSource(0)).Read(v)
+	for i := 0; i < opt.Keys; i++ {
+		if err := d.Set("bench"+strconv.Itoa(i), v); err != nil {
+			return BenchResult{}, err
+		}
+	}
+	l := make([][]time.Duration, opt.Workers)
+	e := make([]error, opt.Workers)
+	w := sync.WaitGroup{}
+	t := time.Now()
+	for i := 0; i < opt.Workers; i++ {
+		w.Add(1)
+		go func(i int) {
+			defer w.Done()
+			r := rand.New(rand.NewSource(int64(i)))
+			for j := i; j < opt.Ops; j += opt.Workers {
+				k := "bench" + strconv.Itoa(r.Intn(opt.Keys))
+				s := time.Now()
+				var err error
+				if r.Float64() < opt.Read {
+					_, err = d.Get(k)
+				} else {
+					err = d.Set(k, v)
+				}
+				l[i] = append(l[i], time.Since(s))
+				if err != nil {
+					e[i] = err
+					return
+				}
+			}
+		}(i)
+	}
+	w.Wait()
+	r := BenchResult{Elapsed: time.Since(t)}
+	for _, err := range e {
+		if err != nil {
+			return r, err
+		}
+	}
+	a := []time.Duration{}
+	for _, x := range l {
+		a = append(a, x...)
+	}
+	r.Ops = len(a)
+	if r.Ops == 0 {
+		return r, nil
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+	r.P50 = a[r.Ops*50/100]
+	r.P90 = a[r.Ops*90/100]
+	r.P99 = a[r.Ops*99/100]
+	r.Max = a[r.Ops-1]
+	return r, nil
+}
@@ -0,0 +1,133 @@
+package acdb
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// mutexDriver serialises every call with a plain mutex, the way Client locked before it used a read-write lock.
+type mutexDriver struct {
+	d Driver
+	m sync.Mutex
+}
+
+func (d *mutexDriver) Get(k string) ([]byte, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.d.Get(k)
+}
+
+func (d *mutexDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.d.Set(k, v)
+}
+
+func (d *mutexDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.d.Del(k)
+}
+
+func benchmarkGetParallel(b *testing.B, d Driver) {
+	for i := 0; i < 1024; i++ {
+		if err := d.Set(strconv.Itoa(i), []byte("value")); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if _, err := d.Get(strconv.Itoa(i % 1024)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetParallelRWMutex(b *testing.B) {
+	c := NewClient(NewMapDriver(b.TempDir()))
+	c.Log(0)
+	benchmarkGetParallel(b, c)
+}
+
+func BenchmarkGetParallelMutex(b *testing.B) {
+	benchmarkGetParallel(b, &mutexDriver{d: NewMapDriver(b.TempDir())})
+}
+
+func benchmarkDriver(b *testing.B, d Driver) {
+	v := make([]byte, 256)
+	for i := 0; i < 1024; i++ {
+		if err := d.Set(strconv.Itoa(i), v); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Run("Get", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := d.Get(strconv.Itoa(i % 1024)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := d.Set(strconv.Itoa(i%1024), v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Del", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			k := strconv.Itoa(i % 1024)
+			if err := d.Set(k, v); err != nil {
+				b.Fatal(err)
+			}
+			if err := d.Del(k); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMemDriver(b *testing.B) {
+	benchmarkDriver(b, NewMemDriver())
+}
+
+func BenchmarkDocDriver(b *testing.B) {
+	benchmarkDriver(b, NewDocDriver(b.TempDir()))
+}
+
+func BenchmarkLruDriver(b *testing.B) {
+	benchmarkDriver(b, NewLruDriver(2048))
+}
+
+func BenchmarkMapDriver(b *testing.B) {
+	benchmarkDriver(b, NewMapDriver(b.TempDir()))
+}
+
+func BenchmarkLfuDriver(b *testing.B) {
+	benchmarkDriver(b, NewLfuDriver(2048))
+}
+
+func BenchmarkArcDriver(b *testing.B) {
+	benchmarkDriver(b, NewArcDriver(2048))
+}
+
+func BenchmarkGzipDriver(b *testing.B) {
+	benchmarkDriver(b, NewGzipDriver(NewMemDriver()))
+}
+
+func BenchmarkSignDriver(b *testing.B) {
+	benchmarkDriver(b, NewSignDriver(NewMemDriver()))
+}
+
+func BenchmarkMetaDriver(b *testing.B) {
+	benchmarkDriver(b, NewMetaDriver(NewMemDriver()))
+}
+
+func BenchmarkClient(b *testing.B) {
+	c := Mem()
+	c.Log(0)
+	benchmarkDriver(b, c)
+}
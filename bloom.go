@@ -0,0 +1,87 @@
+package acdb
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"github.com/godump/doa"
+)
+
+// bloom is a bloom filter of keys. Keys can't be removed, so deleted keys stay as false positives.
+type bloom struct {
+	m    sync.RWMutex
+	bits []uint64
+	k    uint64
+}
+
+// newBloom returns a bloom filter sized for n keys with a false positive rate of p, which must be between 0 and 1
+// exclusive.
+func newBloom(n int, p float64) *bloom {
+	doa.Doa(p > 0 && p < 1)
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloom{bits: make([]uint64, (m+63)/64), k: k}
+}
+
+// hash returns the two hashes from which the bit positions of a key are derived.
+func (b *bloom) hash(k string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(k))
+	x := h.Sum64()
+	return x, x>>33 | x<<31 | 1
+}
+
+// add a key.
+func (b *bloom) add(k string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	n := uint64(len(b.bits)) * 64
+	x, y := b.hash(k)
+	for i := uint64(0); i < b.k; i++ {
+		j := (x + i*y) % n
+		b.bits[j/64] |= 1 << (j % 64)
+	}
+}
+
+// has reports whether a key may have been added.
+func (b *bloom) has(k string) bool {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	n := uint64(len(b.bits)) * 64
+	x, y := b.hash(k)
+	for i := uint64(0); i < b.k; i++ {
+		j := (x + i*y) % n
+		if b.bits[j/64]&(1<<(j%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bloom enables a bloom filter of the keys on the file system, sized for n keys with a false positive rate of p, so
+// that lookups of keys which were never stored return ErrNotExist without touching the disk. The rate must be between
+// 0 and 1 exclusive. The filter is built from the root directory when called, which must happen before the driver is
+// in use. Keys written by other processes afterwards are only seen if Watch is running. Deleted keys stay in the
+// filter until it is rebuilt.
+func (d *MapDriver) Bloom(n int, p float64) error {
+	l, err := d.doc.list()
+	if err != nil {
+		return err
+	}
+	if len(l) > n {
+		n = len(l)
+	}
+	b := newBloom(n, p)
+	for _, e := range l {
+		b.add(e.Name())
+	}
+	d.bloom = b
+	return nil
+}
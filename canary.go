@@ -0,0 +1,87 @@
+package acdb
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+)
+
+// CanaryDriver safely validates a new backend under real traffic. Writes go to both the base and the candidate driver,
+// and a fraction of reads is served by the candidate and compared with the base. Mismatches are counted and reported.
+// Errors of the candidate never fail a write, and reads fall back to the base if the candidate fails.
+type CanaryDriver struct {
+	base     Driver
+	cand     Driver
+	rate     float64
+	compared uint64
+	mismatch uint64
+	report   func(k string)
+}
+
+// NewCanaryDriver returns a CanaryDriver which serves the given fraction of reads, between 0 and 1, from cand.
+func NewCanaryDriver(base Driver, cand Driver, rate float64) *CanaryDriver {
+	return &CanaryDriver{base: base, cand: cand, rate: rate}
+}
+
+// OnMismatch sets a function called with the key of each mismatch. It must be called before the driver is used.
+func (d *CanaryDriver) OnMismatch(f func(k string)) {
+	d.report = f
+}
+
+// Compared returns the number of reads compared between the two drivers.
+func (d *CanaryDriver) Compared() uint64 {
+	return atomic.LoadUint64(&d.compared)
+}
+
+// Mismatch returns the number of compared reads, and writes, on which the two drivers disagreed.
+func (d *CanaryDriver) Mismatch() uint64 {
+	return atomic.LoadUint64(&d.mismatch)
+}
+
+// miss records a mismatch of a key.
+func (d *CanaryDriver) miss(k string) {
+	atomic.AddUint64(&d.mismatch, 1)
+	if d.report != nil {
+		d.report(k)
+	}
+}
+
+// Get the value of a key.
+func (d *CanaryDriver) Get(k string) ([]byte, error) {
+	if rand.Float64() >= d.rate {
+		return d.base.Get(k)
+	}
+	vc, ec := d.cand.Get(k)
+	vb, eb := d.base.Get(k)
+	atomic.AddUint64(&d.compared, 1)
+	nc := errors.Is(ec, ErrNotExist)
+	nb := errors.Is(eb, ErrNotExist)
+	if nc != nb || (ec == nil && eb == nil && !bytes.Equal(vc, vb)) || (ec != nil && !nc) {
+		d.miss(k)
+	}
+	if ec != nil && !nc {
+		return vb, eb
+	}
+	return vc, ec
+}
+
+// Set the value of a key.
+func (d *CanaryDriver) Set(k string, v []byte) error {
+	if err := d.base.Set(k, v); err != nil {
+		return err
+	}
+	if d.cand.Set(k, v) != nil {
+		d.miss(k)
+	}
+	return nil
+}
+
+// Del the value of a key.
+func (d *CanaryDriver) Del(k string) error {
+	err := d.base.Del(k)
+	if ec := d.cand.Del(k); ec != nil && !errors.Is(ec, ErrNotExist) {
+		d.miss(k)
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+// Command acdb-cli is an interactive client for acdb stores. It opens a local driver directly, or a remote Redis
+// server through RedisDriver, and reads commands from standard input.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godump/acdb"
+)
+
+var (
+	flDriver   = flag.String("driver", "map", "driver: mem, doc, lru, map or redis")
+	flRoot     = flag.String("root", ".", "root directory of doc and map drivers")
+	flFsync    = flag.String("fsync", "never", "sync policy of doc and map drivers: never, everysec or always")
+	flSize     = flag.Int("size", 1024, "size of the lru driver")
+	flAddr     = flag.String("addr", "127.0.0.1:6379", "address of the redis server")
+	flPassword = flag.String("password", "", "password of the redis server")
+	flDB       = flag.Int("db", 0, "database of the redis server")
+)
+
+const help = `get <key>            print the value of a key
+set <key> <value>    set the value of a key
+del <key>            delete a key
+copy <src> <dst>     copy the value of src to dst
+rename <src> <dst>   move the value of src to dst
+add <key> <n>        add n to the counter stored in a key
+keys [prefix]        list keys
+scan [prefix]        list keys and values
+ttl <key>            print the time left before a key expires
+touch <key>          restart the time to live of a key
+bench [ops] [read]   run a benchmark with ops operations, read is the fraction of gets, against a scratch store of
+                     the same driver, never against the open store; not available with redis
+help                 print this help
+exit                 quit`
+
+// open returns a client for the driver selected by flags.
+func open() *acdb.Client {
+	p, b := map[string]acdb.SyncPolicy{"never": acdb.SyncNever, "everysec": acdb.SyncEverySec, "always": acdb.SyncAlways}[*flFsync]
+	if !b {
+		fmt.Fprintln(os.Stderr, "acdb-cli: unknown sync policy", *flFsync)
+		os.Exit(2)
+	}
+	switch *flDriver {
+	case "mem":
+		return acdb.Mem()
+	case "doc":
+		d := acdb.NewDocDriver(*flRoot)
+		d.Fsync(p)
+		return acdb.NewClient(d)
+	case "lru":
+		return acdb.Lru(*flSize)
+	case "map":
+		d := acdb.NewMapDriver(*flRoot)
+		d.Fsync(p)
+		return acdb.NewClient(d)
+	case "redis":
+		return acdb.Redis(*flAddr, *flPassword, *flDB)
+	}
+	fmt.Fprintln(os.Stderr, "acdb-cli: unknown driver", *flDriver)
+	os.Exit(2)
+	return nil
+}
+
+// scratch returns an empty driver of the kind selected by flags, for benchmarks, and a function removing it.
+func scratch() (acdb.Driver, func(), error) {
+	switch *flDriver {
+	case "mem":
+		return acdb.NewMemDriver(), func() {}, nil
+	case "lru":
+		return acdb.NewLruDriver(*flSize), func() {}, nil
+	case "doc", "map":
+		root, err := os.MkdirTemp("", "acdb-bench")
+		if err != nil {
+			return nil, nil, err
+		}
+		p := map[string]acdb.SyncPolicy{"never": acdb.SyncNever, "everysec": acdb.SyncEverySec, "always": acdb.SyncAlways}[*flFsync]
+		if *flDriver == "doc" {
+			d := acdb.NewDocDriver(root)
+			d.Fsync(p)
+			return d, func() { d.Fsync(acdb.SyncNever); os.RemoveAll(root) }, nil
+		}
+		d := acdb.NewMapDriver(root)
+		d.Fsync(p)
+		return d, func() { d.Fsync(acdb.SyncNever); os.RemoveAll(root) }, nil
+	}
+	return nil, nil, fmt.Errorf("bench is not available with the %s driver", *flDriver)
+}
+
+// show formats a value for printing. Json values are pretty printed.
+func show(v []byte) string {
+	b := &bytes.Buffer{}
+	if json.Valid(v) && json.Indent(b, v, "", "  ") == nil {
+		return b.String()
+	}
+	return strconv.Quote(string(v))
+}
+
+// exec runs a single command line.
+func exec(db *acdb.Client, args []string) error {
+	arity := map[string]int{"get": 2, "set": 3, "del": 2, "copy": 3, "rename": 3, "add": 3, "keys": 1, "scan": 1, "ttl": 2, "touch": 2}
+	if n, b := arity[args[0]]; b && len(args) < n {
+		return fmt.Errorf("usage: %s", args[0])
+	}
+	switch args[0] {
+	case "get":
+		v, err := db.Get(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(show(v))
+	case "set":
+		return db.Set(args[1], []byte(strings.Join(args[2:], " ")))
+	case "del":
+		return db.Del(args[1])
+	case "copy":
+		return db.Copy(args[1], args[2])
+	case "rename":
+		return db.Rename(args[1], args[2])
+	case "add":
+		n, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		r, err := db.Counter(args[1]).Add(n)
+		if err != nil {
+			return err
+		}
+		fmt.Println(r)
+	case "keys", "scan":
+		p := ""
+		if len(args) > 1 {
+			p = args[1]
+		}
+		l := []acdb.KV{}
+		err := db.Scan(func(k string, v []byte) error {
+			if strings.HasPrefix(k, p) {
+				l = append(l, acdb.KV{K: k, V: v})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sort.Slice(l, func(i, j int) bool { return l[i].K < l[j].K })
+		for _, e := range l {
+			if args[0] == "keys" {
+				fmt.Println(e.K)
+			} else {
+				fmt.Println(e.K, show(e.V))
+			}
+		}
+	case "ttl":
+		h, err := db.Head(args[1])
+		if err != nil {
+			return err
+		}
+		if h.Expire.IsZero() {
+			fmt.Println("never")
+		} else {
+			fmt.Println(time.Until(h.Expire).Round(time.Second))
+		}
+	case "touch":
+		return db.Touch(args[1])
+	case "bench":
+		o := acdb.BenchOption{Workers: 8, Ops: 100000, Read: 0.9, Keys: 1024, Size: 128}
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return err
+			}
+			o.Ops = n
+		}
+		if len(args) > 2 {
+			n, err := strconv.ParseFloat(args[2], 64)
+			if err != nil {
+				return err
+			}
+			o.Read = n
+		}
+		d, done, err := scratch()
+		if err != nil {
+			return err
+		}
+		defer done()
+		r, err := acdb.Bench(d, o)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d ops in %s, %.0f ops/s, p50 %s, p90 %s, p99 %s, max %s\n",
+			r.Ops, r.Elapsed, r.Throughput(), r.P50, r.P90, r.P99, r.Max)
+	case "help":
+		fmt.Println(help)
+	default:
+		return fmt.Errorf("unknown command %s, try help", args[0])
+	}
+	return nil
+}
+
+func main() {
+	flag.Parse()
+	db := open()
+	db.Log(0)
+	s := bufio.NewScanner(os.Stdin)
+	s.Buffer(nil, 64*1024*1024)
+	for {
+		fmt.Print("acdb> ")
+		if !s.Scan() {
+			fmt.Println()
+			return
+		}
+		args := strings.Fields(s.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return
+		}
+		if err := exec(db, args); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package acdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Codec is the interface that wraps the Marshal and Unmarshal method. It is used by SetEncode and GetDecode to convert
+// between go values and bytes.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// JsonCodec is the default codec, it uses encoding/json.
+type JsonCodec struct{}
+
+// Marshal returns the json encoding of v.
+func (c JsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal parses the json-encoded data and stores the result in the value pointed to by v.
+func (c JsonCodec) Unmarshal(b []byte, v interface{}) error {
+	return json.Unmarshal(b, v)
+}
+
+// TimeCodec encodes time.Time values as text in the given layout.
+type TimeCodec struct {
+	Layout string
+}
+
+// Marshal returns the textual representation of a time.Time or *time.Time.
+func (c TimeCodec) Marshal(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return []byte(t.Format(c.Layout)), nil
+	case *time.Time:
+		return []byte(t.Format(c.Layout)), nil
+	}
+	return nil, fmt.Errorf("acdb: time codec can't marshal %T", v)
+}
+
+// Unmarshal parses the textual representation of a time and stores it in v, which must be a *time.Time.
+func (c TimeCodec) Unmarshal(b []byte, v interface{}) error {
+	t, ok := v.(*time.Time)
+	if !ok {
+		return fmt.Errorf("acdb: time codec can't unmarshal into %T", v)
+	}
+	r, err := time.Parse(c.Layout, string(b))
+	if err != nil {
+		return err
+	}
+	*t = r
+	return nil
+}
+
+// Codec sets the default codec used by SetEncode and GetDecode.
+func (e *Client) Codec(c Codec) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.codec = c
+}
+
+// Register sets the codec used for values of the same type as v. Pointers are dereferenced, so registering time.Time{}
+// applies to both time.Time and *time.Time.
+func (e *Client) Register(v interface{}, c Codec) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.codecs[indirect(reflect.TypeOf(v))] = c
+}
+
+// codecOf returns the codec for values of the same type as v.
+func (e *Client) codecOf(v interface{}) Codec {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	if c, b := e.codecs[indirect(reflect.TypeOf(v))]; b {
+		return c
+	}
+	return e.codec
+}
+
+// indirect returns the type pointed to by t.
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,88 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// errComputePanic is returned to the callers waiting for a computation whose function panicked.
+var errComputePanic = errors.New("acdb: compute function panicked")
+
+// flight is a computation in progress, shared by every caller of GetOrCompute for the same key.
+type flight struct {
+	done chan struct{}
+	b    []byte
+	err  error
+}
+
+// GetOrCompute decodes the value of a key into v. If the key does not exist or has expired, fn is called to compute
+// the value, which is stored for ttl and decoded into v. Only one call of fn per key runs at a time, concurrent callers
+// wait for its result instead of computing the value again. To avoid a stampede when a hot key expires, the value is
+// also recomputed early, with a probability growing as expiry approaches and with the time fn took (XFetch).
+//
+// Values are stored with their expiry and compute time in front of the encoded value, so keys used with GetOrCompute
+// must only be read with GetOrCompute.
+func (e *Client) GetOrCompute(k string, ttl time.Duration, v interface{}, fn func() (interface{}, error)) error {
+	c := e.codecOf(v)
+	e.m.RLock()
+	p, err := e.driver.Get(k)
+	e.m.RUnlock()
+	if err == nil && len(p) >= 16 {
+		x := time.Unix(0, int64(binary.BigEndian.Uint64(p)))
+		d := time.Duration(binary.BigEndian.Uint64(p[8:]))
+		if time.Now().Add(time.Duration(-float64(d) * math.Log(1-rand.Float64()))).Before(x) {
+			return c.Unmarshal(p[16:], v)
+		}
+	}
+	e.fm.Lock()
+	f, b := e.flight[k]
+	if !b {
+		f = &flight{done: make(chan struct{})}
+		e.flight[k] = f
+	}
+	e.fm.Unlock()
+	if !b {
+		func() {
+			defer func() {
+				e.fm.Lock()
+				delete(e.flight, k)
+				e.fm.Unlock()
+				close(f.done)
+			}()
+			f.err = errComputePanic
+			f.b, f.err = e.compute(k, ttl, c, fn)
+		}()
+	}
+	<-f.done
+	if f.err != nil {
+		return f.err
+	}
+	return c.Unmarshal(f.b, v)
+}
+
+// compute calls fn and stores its result for ttl. It returns the encoded value.
+func (e *Client) compute(k string, ttl time.Duration, c Codec, fn func() (interface{}, error)) ([]byte, error) {
+	t := time.Now()
+	r, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	d := time.Since(t)
+	b, err := c.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	v := make([]byte, 16+len(b))
+	binary.BigEndian.PutUint64(v, uint64(time.Now().Add(ttl).UnixNano()))
+	binary.BigEndian.PutUint64(v[8:], uint64(d))
+	copy(v[16:], b)
+	e.m.Lock()
+	defer e.m.Unlock()
+	if err := e.set(k, v); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,80 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Counter is an int64 stored in a key. It is stored as 8 big-endian bytes instead of json text. A key which does not
+// exist is considered to be zero. On a RedisDriver, the binary value can't be read or changed by redis INCRBY or by
+// RedisDriver.Add, which use decimal text, so a key must be used with one or the other.
+type Counter struct {
+	client *Client
+	k      string
+	min    int64
+	max    int64
+}
+
+// Counter returns the Counter stored in key k.
+func (e *Client) Counter(k string) *Counter {
+	return &Counter{client: e, k: k, min: math.MinInt64, max: math.MaxInt64}
+}
+
+// Bound limits the counter to [min, max]. Adds that would leave the range fail with ErrOverflow.
+func (c *Counter) Bound(min int64, max int64) *Counter {
+	c.min = min
+	c.max = max
+	return c
+}
+
+// get returns the value of the counter. The caller must hold the lock.
+func (c *Counter) get() (int64, error) {
+	b, err := c.client.driver.Get(c.k)
+	if errors.Is(err, ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, errors.New("acdb: value is not a counter")
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// set the value of the counter. The caller must hold the lock.
+func (c *Counter) set(n int64) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(n))
+	return c.client.set(c.k, b)
+}
+
+// Add adds n to the counter and returns the new value.
+func (c *Counter) Add(n int64) (int64, error) {
+	c.client.m.Lock()
+	defer c.client.m.Unlock()
+	r, err := c.get()
+	if err != nil {
+		return 0, err
+	}
+	if (n > 0 && r > c.max-n) || (n < 0 && r < c.min-n) {
+		return r, ErrOverflow
+	}
+	r += n
+	return r, c.set(r)
+}
+
+// Get returns the value of the counter.
+func (c *Counter) Get() (int64, error) {
+	c.client.m.Lock()
+	defer c.client.m.Unlock()
+	return c.get()
+}
+
+// Reset sets the counter to zero.
+func (c *Counter) Reset() error {
+	c.client.m.Lock()
+	defer c.client.m.Unlock()
+	return c.set(0)
+}
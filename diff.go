@@ -0,0 +1,86 @@
+package acdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// DiffReport describes the differences between two stores. All lists are sorted.
+type DiffReport struct {
+	// OnlyA holds the keys which exist only in the first store.
+	OnlyA []string
+	// OnlyB holds the keys which exist only in the second store.
+	OnlyB []string
+	// Differ holds the keys which exist in both stores with different values.
+	Differ []string
+}
+
+// Diff compares all keys of a and b. Both drivers must implement Scanner. If jsonAware is true, two values which are
+// both valid json are compared by meaning, so formatting and the order of object keys do not matter.
+func Diff(a Driver, b Driver, jsonAware bool) (DiffReport, error) {
+	r := DiffReport{OnlyA: []string{}, OnlyB: []string{}, Differ: []string{}}
+	sa, ok := a.(Scanner)
+	if !ok {
+		return r, ErrUnsupported
+	}
+	sb, ok := b.(Scanner)
+	if !ok {
+		return r, ErrUnsupported
+	}
+	m := map[string][sha256.Size]byte{}
+	err := sa.Scan(func(k string, v []byte) error {
+		m[k] = sha256.Sum256(v)
+		return nil
+	})
+	if err != nil {
+		return r, err
+	}
+	err = sb.Scan(func(k string, v []byte) error {
+		h, ok := m[k]
+		if !ok {
+			r.OnlyB = append(r.OnlyB, k)
+			return nil
+		}
+		delete(m, k)
+		if h == sha256.Sum256(v) {
+			return nil
+		}
+		if jsonAware {
+			w, err := a.Get(k)
+			if err != nil {
+				return err
+			}
+			if jsonEqual(w, v) {
+				return nil
+			}
+		}
+		r.Differ = append(r.Differ, k)
+		return nil
+	})
+	if err != nil {
+		return r, err
+	}
+	for k := range m {
+		r.OnlyA = append(r.OnlyA, k)
+	}
+	sort.Strings(r.OnlyA)
+	sort.Strings(r.OnlyB)
+	sort.Strings(r.Differ)
+	return r, nil
+}
+
+// jsonEqual reports whether a and b are valid json documents with the same meaning.
+func jsonEqual(a []byte, b []byte) bool {
+	var x, y interface{}
+	da := json.NewDecoder(bytes.NewReader(a))
+	da.UseNumber()
+	db := json.NewDecoder(bytes.NewReader(b))
+	db.UseNumber()
+	if da.Decode(&x) != nil || db.Decode(&y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
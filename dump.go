@@ -0,0 +1,174 @@
+package acdb
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+)
+
+// KV is a key and its value.
+type KV struct {
+	K string `json:"k"`
+	V []byte `json:"v"`
+}
+
+// orderedScanner is implemented by scanners which visit keys in ascending order, or are able to do so without holding
+// all entries in memory.
+type orderedScanner interface {
+	scanOrdered(f func(k string, v []byte) error) error
+}
+
+// scanSorted calls f for each key of s and its value in ascending order of keys. Ordered scanners are scanned directly
+// and drivers implementing Ranger are read page by page. Other scanners have all their entries held in memory, as
+// reading the values again could disturb the order of cache drivers.
+func scanSorted(s Scanner, f func(k string, v []byte) error) error {
+	if o, b := s.(orderedScanner); b {
+		return o.scanOrdered(f)
+	}
+	if r, b := s.(Ranger); b {
+		start := ""
+		for {
+			l, err := r.Range(start, "", 1024)
+			if err != nil {
+				return err
+			}
+			for _, e := range l {
+				if err := f(e.K, e.V); err != nil {
+					return err
+				}
+			}
+			if len(l) < 1024 {
+				return nil
+			}
+			start = l[len(l)-1].K + "\x00"
+		}
+	}
+	l := []KV{}
+	err := s.Scan(func(k string, v []byte) error {
+		l = append(l, KV{K: k, V: v})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].K < l[j].K })
+	for _, e := range l {
+		if err := f(e.K, e.V); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanOrdered calls f for each key and its value. Files are listed in ascending order of names, so Scan is ordered.
+func (d *DocDriver) scanOrdered(f func(k string, v []byte) error) error {
+	return d.Scan(f)
+}
+
+// scanOrdered calls f for each key and its value in ascending order of keys.
+func (d *MapDriver) scanOrdered(f func(k string, v []byte) error) error {
+	return d.Scan(f)
+}
+
+// scanOrdered calls f for each key and its value in ascending order of keys.
+func (d *GitDriver) scanOrdered(f func(k string, v []byte) error) error {
+	return d.Scan(f)
+}
+
+// scanOrdered calls f for each key and its value in ascending order of keys, skipping signatures. The inner driver
+// must implement Scanner.
+func (d *SignDriver) scanOrdered(f func(k string, v []byte) error) error {
+	s, b := d.inner.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return scanSorted(s, func(k string, v []byte) error {
+		if isSig(k) {
+			return nil
+		}
+		return f(k, v)
+	})
+}
+
+// scanOrdered calls f for each key and its value in ascending order of keys, as the driver allows. The driver must
+// implement Scanner.
+func (e *Client) scanOrdered(f func(k string, v []byte) error) error {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	s, b := e.driver.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return scanSorted(s, f)
+}
+
+// Dump writes all keys of s to w as json lines. Each line is a KV object, values are base64 encoded. Keys are written
+// in ascending order, so two dumps of identical data are byte-identical.
+func Dump(s Scanner, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	e := json.NewEncoder(b)
+	err := scanSorted(s, func(k string, v []byte) error {
+		return e.Encode(KV{K: k, V: v})
+	})
+	if err != nil {
+		return err
+	}
+	return b.Flush()
+}
+
+// Load reads json lines written by Dump from r and sets each key in d.
+func Load(r io.Reader, d Driver) error {
+	e := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var kv KV
+		err := e.Decode(&kv)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := d.Set(kv.K, kv.V); err != nil {
+			return err
+		}
+	}
+}
+
+// DumpCSV writes all keys of s to w as csv records of key and base64 encoded value, in ascending order of keys.
+func DumpCSV(s Scanner, w io.Writer) error {
+	c := csv.NewWriter(w)
+	err := scanSorted(s, func(k string, v []byte) error {
+		return c.Write([]string{k, base64.StdEncoding.EncodeToString(v)})
+	})
+	if err != nil {
+		return err
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// LoadCSV reads csv records written by DumpCSV from r and sets each key in d.
+func LoadCSV(r io.Reader, d Driver) error {
+	c := csv.NewReader(r)
+	c.FieldsPerRecord = 2
+	for {
+		l, err := c.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		v, err := base64.StdEncoding.DecodeString(l[1])
+		if err != nil {
+			return err
+		}
+		if err := d.Set(l[0], v); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package acdb
+
+import (
+	"errors"
+	"os"
+)
+
+// Errors returned by drivers and by Client. Drivers wrap their own errors so that callers can always test for these
+// with errors.Is, whatever the driver.
+var (
+	// ErrNotExist is returned when a key does not exist. It is the same error as os.ErrNotExist.
+	ErrNotExist = os.ErrNotExist
+	// ErrHasExist is returned when a key which should not exist does.
+	ErrHasExist = errors.New("acdb: key has exist")
+	// ErrReadOnly is returned when writing to a read-only driver.
+	ErrReadOnly = errors.New("acdb: driver is read-only")
+	// ErrTooLarge is returned when a key or a value exceeds a configured limit.
+	ErrTooLarge = errors.New("acdb: key or value too large")
+	// ErrUnsupported is returned when the driver does not implement an optional capability.
+	ErrUnsupported = errors.New("acdb: operation not supported by driver")
+	// ErrLocked is returned when a root or a named lock is held by another process.
+	ErrLocked = errors.New("acdb: locked by another process")
+	// ErrConflict is returned when a conditional write finds a different version than expected.
+	ErrConflict = errors.New("acdb: version conflict")
+	// ErrOverflow is returned when a counter would exceed its bounds.
+	ErrOverflow = errors.New("acdb: counter overflow")
+	// ErrSignature is returned when a value is not signed by any trusted key.
+	ErrSignature = errors.New("acdb: invalid signature")
+	// ErrNoMeta is returned when a value read by MetaDriver has no metadata header.
+	ErrNoMeta = errors.New("acdb: value has no metadata")
+	// ErrCorrupt is returned when a stored value does not match its checksum.
+	ErrCorrupt = errors.New("acdb: value is corrupt")
+)
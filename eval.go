@@ -0,0 +1,32 @@
+package acdb
+
+// locked is a Driver which runs on a client whose lock is already held, so that writes still go through the
+// validators, limits and observers of the client.
+type locked struct {
+	e *Client
+}
+
+// Get the value of a key.
+func (d *locked) Get(k string) ([]byte, error) {
+	return d.e.driver.Get(k)
+}
+
+// Set the value of a key.
+func (d *locked) Set(k string, v []byte) error {
+	return d.e.set(k, v)
+}
+
+// Del the value of a key.
+func (d *locked) Del(k string) error {
+	return d.e.del(k)
+}
+
+// Eval runs fn atomically, no other operation on the client happens in between, so multi-step operations such as rate
+// limiters, queues or conditional updates need neither retries nor a script engine. fn must only use the driver it is
+// given, not the client, and must return quickly since the client is locked meanwhile. Writes made before fn returns
+// an error are kept.
+func (e *Client) Eval(fn func(d Driver) error) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return fn(&locked{e: e})
+}
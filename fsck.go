@@ -0,0 +1,89 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path"
+)
+
+// sum returns a value followed by its CRC-32 (IEEE) checksum in 4 big-endian bytes.
+func sum(v []byte) []byte {
+	r := make([]byte, len(v)+4)
+	copy(r, v)
+	binary.BigEndian.PutUint32(r[len(v):], crc32.ChecksumIEEE(v))
+	return r
+}
+
+// unsum verifies and strips the checksum appended by sum.
+func unsum(b []byte) ([]byte, error) {
+	if len(b) < 4 {
+		return nil, ErrCorrupt
+	}
+	v := b[:len(b)-4]
+	if binary.BigEndian.Uint32(b[len(v):]) != crc32.ChecksumIEEE(v) {
+		return nil, ErrCorrupt
+	}
+	return v, nil
+}
+
+// Checksum makes the driver store a CRC-32 checksum after each value and verify it on every read, returning
+// ErrCorrupt for values damaged on disk or torn by an unclean shutdown. Files written without checksums read as corrupt,
+// so it must be enabled on a new root and every time a driver is opened on it, before the driver is used.
+func (d *DocDriver) Checksum(b bool) {
+	d.sum = b
+}
+
+// Fsck reads every value and returns the keys whose value is corrupt. If quarantine is not empty, their files are
+// moved into that directory, so that the keys no longer exist and the damaged data can be inspected later.
+func (d *DocDriver) Fsck(quarantine string) ([]string, error) {
+	l, err := d.list()
+	if err != nil {
+		return nil, err
+	}
+	r := []string{}
+	for _, e := range l {
+		_, err := d.Get(e.Name())
+		if errors.Is(err, ErrNotExist) {
+			continue
+		}
+		if !errors.Is(err, ErrCorrupt) {
+			if err != nil {
+				return r, err
+			}
+			continue
+		}
+		r = append(r, e.Name())
+		if quarantine == "" {
+			continue
+		}
+		if err := os.MkdirAll(quarantine, 0755); err != nil {
+			return r, err
+		}
+		if err := os.Rename(d.path(e.Name()), path.Join(quarantine, e.Name())); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// Checksum makes the file system copies carry checksums, see DocDriver.Checksum.
+func (d *MapDriver) Checksum(b bool) {
+	d.doc.Checksum(b)
+}
+
+// Fsck checks the file system copies, see DocDriver.Fsck. Pending writes are flushed first, and quarantined keys are
+// dropped from the cache.
+func (d *MapDriver) Fsck(quarantine string) ([]string, error) {
+	if err := d.Flush(); err != nil {
+		return nil, err
+	}
+	r, err := d.doc.Fsck(quarantine)
+	if quarantine != "" {
+		for _, k := range r {
+			d.lru.Del(k)
+		}
+	}
+	return r, err
+}
@@ -0,0 +1,147 @@
+package acdb
+
+import (
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// SyncPolicy controls when DocDriver asks the operating system to flush writes to the disk.
+type SyncPolicy int
+
+// Sync policies, from the fastest to the safest.
+const (
+	// SyncNever leaves flushing to the operating system, a crash may lose any recent write.
+	SyncNever SyncPolicy = iota
+	// SyncEverySec flushes written files once a second, a crash loses at most about a second of writes.
+	SyncEverySec
+	// SyncAlways flushes every write before returning, a crash loses no acknowledged write.
+	SyncAlways
+)
+
+// syncer holds the sync policy of a DocDriver and, in SyncEverySec, the files written since the last flush. Once the
+// policy is replaced, dirty is nil and late writes are flushed as they happen.
+type syncer struct {
+	p     SyncPolicy
+	dirty map[string]struct{}
+	err   error
+	m     *sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// fsync flushes a file and the directory holding it. Errors of the directory are ignored, since not every platform is
+// able to flush directories.
+func fsync(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if d, err := os.Open(path.Dir(name)); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// flush flushes the files written since the last flush. Files removed meanwhile are skipped. The last flush stops
+// collecting files.
+func (s *syncer) flush(last bool) error {
+	s.m.Lock()
+	l := s.dirty
+	s.dirty = map[string]struct{}{}
+	if last {
+		s.dirty = nil
+	}
+	s.m.Unlock()
+	var r error
+	for name := range l {
+		if err := fsync(name); err != nil && !os.IsNotExist(err) {
+			r = err
+		}
+	}
+	return r
+}
+
+// Fsync sets the sync policy of the driver, trading throughput against the writes lost on a crash. The default is
+// SyncNever. It may be called while the driver is in use. In SyncEverySec, files are flushed by a background goroutine,
+// whose errors are reported by SyncErr; on shutdown, switch to another policy to stop it and flush the last writes.
+func (d *DocDriver) Fsync(p SyncPolicy) error {
+	s := &syncer{p: p, dirty: map[string]struct{}{}, m: &sync.Mutex{}}
+	if p == SyncEverySec {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go func() {
+			defer close(s.done)
+			t := time.NewTicker(time.Second)
+			defer t.Stop()
+			for {
+				select {
+				case <-t.C:
+					err := s.flush(false)
+					s.m.Lock()
+					s.err = err
+					s.m.Unlock()
+				case <-s.stop:
+					return
+				}
+			}
+		}()
+	}
+	o := d.fs.Swap(s)
+	if o == nil || o.stop == nil {
+		return nil
+	}
+	close(o.stop)
+	<-o.done
+	return o.flush(true)
+}
+
+// SyncErr returns the error of the last background flush in SyncEverySec, or nil if it succeeded.
+func (d *DocDriver) SyncErr() error {
+	s := d.fs.Load()
+	if s == nil {
+		return nil
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.err
+}
+
+// sync makes a written file durable according to the sync policy. For a removed file, name is its directory.
+func (d *DocDriver) sync(name string) error {
+	s := d.fs.Load()
+	if s == nil {
+		return nil
+	}
+	switch s.p {
+	case SyncAlways:
+		return fsync(name)
+	case SyncEverySec:
+		s.m.Lock()
+		if s.dirty == nil {
+			s.m.Unlock()
+			return fsync(name)
+		}
+		s.dirty[name] = struct{}{}
+		s.m.Unlock()
+	}
+	return nil
+}
+
+// Fsync sets the sync policy of the file system copies, see DocDriver.Fsync. In write-back mode, the policy applies
+// when pending writes are flushed.
+func (d *MapDriver) Fsync(p SyncPolicy) error {
+	return d.doc.Fsync(p)
+}
+
+// SyncErr returns the error of the last background flush of the file system copies, see DocDriver.SyncErr.
+func (d *MapDriver) SyncErr() error {
+	return d.doc.SyncErr()
+}
@@ -0,0 +1,130 @@
+package acdb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/godump/doa"
+)
+
+// GitDriver is based on DocDriver and records every Set and Del as a commit in a git repository at its root, giving
+// full history of every key and the ability to push and pull the whole store. It requires the git command.
+type GitDriver struct {
+	doc *DocDriver
+}
+
+// NewGitDriver returns a GitDriver. The repository is initialized if it does not exist.
+func NewGitDriver(root string) *GitDriver {
+	d := &GitDriver{doc: NewDocDriver(root)}
+	if _, err := os.Stat(path.Join(root, ".git")); os.IsNotExist(err) {
+		doa.Nil(doa.Err(d.git("init", "-q")))
+	}
+	return d
+}
+
+// git runs a git command in the root directory and returns its output.
+func (d *GitDriver) git(args ...string) ([]byte, error) {
+	c := exec.Command("git", append([]string{"-C", d.doc.root}, args...)...)
+	c.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=acdb",
+		"GIT_AUTHOR_EMAIL=acdb@localhost",
+		"GIT_COMMITTER_NAME=acdb",
+		"GIT_COMMITTER_EMAIL=acdb@localhost",
+	)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("acdb: git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// commit records the current state of a key.
+func (d *GitDriver) commit(op string, k string) error {
+	if _, err := d.git("add", "-A", "--", k); err != nil {
+		return err
+	}
+	_, err := d.git("commit", "-q", "--allow-empty", "-m", op+" "+k, "--", k)
+	return err
+}
+
+// check rejects keys whose file would be inside the .git directory, where writes could change the configuration of the
+// repository and have git run commands, or outside of the root.
+func (d *GitDriver) check(k string) error {
+	r, err := filepath.Rel(d.doc.root, d.doc.path(k))
+	if err != nil || r == "." || r == ".." || strings.HasPrefix(r, "../") || r == ".git" || strings.HasPrefix(r, ".git/") {
+		return fmt.Errorf("acdb: invalid key %q", k)
+	}
+	return nil
+}
+
+// Get the value of a key.
+func (d *GitDriver) Get(k string) ([]byte, error) {
+	return d.doc.Get(k)
+}
+
+// Set the value of a key. Keys under .git are rejected.
+func (d *GitDriver) Set(k string, v []byte) error {
+	if err := d.check(k); err != nil {
+		return err
+	}
+	if err := d.doc.Set(k, v); err != nil {
+		return err
+	}
+	return d.commit("set", k)
+}
+
+// Del the value of a key. Keys under .git are rejected.
+func (d *GitDriver) Del(k string) error {
+	if err := d.check(k); err != nil {
+		return err
+	}
+	if err := d.doc.Del(k); err != nil {
+		return err
+	}
+	return d.commit("del", k)
+}
+
+// Scan calls f for each key and its value.
+func (d *GitDriver) Scan(f func(k string, v []byte) error) error {
+	return d.doc.Scan(f)
+}
+
+// History returns the hashes of the commits which changed a key, the newest first.
+func (d *GitDriver) History(k string) ([]string, error) {
+	b, err := d.git("log", "--format=%H", "--", k)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(b)), nil
+}
+
+// GetRev returns the value of a key at the given revision. Revisions starting with a dash are rejected, as git would
+// read them as options.
+func (d *GitDriver) GetRev(k string, rev string) ([]byte, error) {
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("acdb: invalid revision %q", rev)
+	}
+	return d.git("show", rev+":"+k)
+}
+
+// Push pushes the store to a remote, for example Push("origin", "master").
+func (d *GitDriver) Push(args ...string) error {
+	_, err := d.git(append([]string{"push", "-q"}, args...)...)
+	return err
+}
+
+// Pull pulls the store from a remote, for example Pull("origin", "master").
+func (d *GitDriver) Pull(args ...string) error {
+	_, err := d.git(append([]string{"pull", "-q"}, args...)...)
+	return err
+}
+
+// Git returns a concurrency-safety Client with GitDriver.
+func Git(root string) *Client { return NewClient(NewGitDriver(root)) }
@@ -0,0 +1,57 @@
+package acdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// GzipDriver wraps a driver whose values under some prefixes are gzip compressed by their producers, and decompresses
+// them on Get, so consumers don't all need the codec. Values which do not start with the gzip magic number are
+// returned as is. Writes are passed through unchanged.
+type GzipDriver struct {
+	inner  Driver
+	prefix []string
+}
+
+// NewGzipDriver returns a GzipDriver which decompresses values of keys under any of the given prefixes.
+func NewGzipDriver(inner Driver, prefix ...string) *GzipDriver {
+	return &GzipDriver{inner: inner, prefix: prefix}
+}
+
+// Get the decompressed value of a key.
+func (d *GzipDriver) Get(k string) ([]byte, error) {
+	v, err := d.inner.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < 2 || v[0] != 0x1f || v[1] != 0x8b {
+		return v, nil
+	}
+	for _, p := range d.prefix {
+		if strings.HasPrefix(k, p) {
+			r, err := gzip.NewReader(bytes.NewReader(v))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(r)
+		}
+	}
+	return v, nil
+}
+
+// GetRaw returns the value of a key as stored, without decompression.
+func (d *GzipDriver) GetRaw(k string) ([]byte, error) {
+	return d.inner.Get(k)
+}
+
+// Set the value of a key. The value is stored as is.
+func (d *GzipDriver) Set(k string, v []byte) error {
+	return d.inner.Set(k, v)
+}
+
+// Del the value of a key.
+func (d *GzipDriver) Del(k string) error {
+	return d.inner.Del(k)
+}
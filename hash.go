@@ -0,0 +1,80 @@
+package acdb
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// hash returns the fields of the hash stored in a key. A key which does not exist is an empty hash. The caller must
+// hold the lock.
+func (e *Client) hash(k string) (map[string][]byte, error) {
+	r := map[string][]byte{}
+	b, err := e.driver.Get(k)
+	if errors.Is(err, ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, json.Unmarshal(b, &r)
+}
+
+// HSet sets a field of the hash stored in a key, creating the hash if needed.
+func (e *Client) HSet(k string, f string, v []byte) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	h, err := e.hash(k)
+	if err != nil {
+		return err
+	}
+	h[f] = v
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return e.set(k, b)
+}
+
+// HGet gets a field of the hash stored in a key. If the field does not exist, ErrNotExist will be returned.
+func (e *Client) HGet(k string, f string) ([]byte, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	h, err := e.hash(e.resolve(k))
+	if err != nil {
+		return nil, err
+	}
+	v, b := h[f]
+	if !b {
+		return nil, ErrNotExist
+	}
+	return v, nil
+}
+
+// HDel dels a field of the hash stored in a key. The key is deleted along with its last field.
+func (e *Client) HDel(k string, f string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	h, err := e.hash(k)
+	if err != nil {
+		return err
+	}
+	if _, b := h[f]; !b {
+		return ErrNotExist
+	}
+	delete(h, f)
+	if len(h) == 0 {
+		return e.del(k)
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return e.set(k, b)
+}
+
+// HGetAll gets all fields of the hash stored in a key.
+func (e *Client) HGetAll(k string) (map[string][]byte, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	return e.hash(e.resolve(k))
+}
@@ -0,0 +1,109 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+// Head describes a value without its body, so callers can decide whether to fetch a large value at all.
+type Head struct {
+	// Size is the size of the value in bytes.
+	Size int64
+	// Sum is the CRC-32 (IEEE) checksum of the value.
+	Sum uint32
+	// Expire is the time the key expires, or the zero time if it never does.
+	Expire time.Time
+}
+
+// Header is implemented by drivers which are able to describe a value without returning it.
+type Header interface {
+	Head(k string) (Head, error)
+}
+
+// Head describes the value of a key.
+func (d *LruDriver) Head(k string) (Head, error) {
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	e, b := d.data.C[k]
+	if !b || time.Since(e.U) > d.data.E {
+		return Head{}, ErrNotExist
+	}
+	return Head{Size: int64(len(e.V)), Sum: crc32.ChecksumIEEE(e.V), Expire: e.U.Add(d.data.E)}, nil
+}
+
+// Head describes the value of a key from its file. The size comes from the file system. With checksums enabled, the
+// checksum is read from the end of the file, otherwise the file is streamed to compute it, without being held in
+// memory.
+func (d *DocDriver) Head(k string) (Head, error) {
+	f, err := os.Open(d.path(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return Head{}, ErrNotExist
+	}
+	if err != nil {
+		return Head{}, err
+	}
+	defer f.Close()
+	i, err := f.Stat()
+	if err != nil {
+		return Head{}, err
+	}
+	if !d.sum {
+		h := crc32.NewIEEE()
+		if _, err := io.Copy(h, f); err != nil {
+			return Head{}, err
+		}
+		return Head{Size: i.Size(), Sum: h.Sum32()}, nil
+	}
+	if i.Size() < 4 {
+		return Head{}, ErrCorrupt
+	}
+	b := make([]byte, 4)
+	if _, err := f.ReadAt(b, i.Size()-4); err != nil {
+		return Head{}, err
+	}
+	return Head{Size: i.Size() - 4, Sum: binary.BigEndian.Uint32(b)}, nil
+}
+
+// Head describes the value of a key. Pending and cached values are described from memory, others from the file
+// system without being read into the cache.
+func (d *MapDriver) Head(k string) (Head, error) {
+	if d.wb != nil {
+		if e, b := d.wb.get(k); b {
+			if e.del {
+				return Head{}, ErrNotExist
+			}
+			return Head{Size: int64(len(e.v)), Sum: crc32.ChecksumIEEE(e.v)}, nil
+		}
+	}
+	if v, err := d.lru.Get(k); err == nil {
+		return Head{Size: int64(len(v)), Sum: crc32.ChecksumIEEE(v)}, nil
+	}
+	if d.bloom != nil && !d.bloom.has(k) {
+		return Head{}, ErrNotExist
+	}
+	if d.neg != nil {
+		if _, b := d.neg.GetExists(k); b {
+			return Head{}, ErrNotExist
+		}
+	}
+	return d.doc.Head(k)
+}
+
+// Head describes the value of a key. If the driver does not implement Header, the value is read and described.
+func (e *Client) Head(k string) (Head, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	k = e.resolve(k)
+	if h, b := e.driver.(Header); b {
+		return h.Head(k)
+	}
+	v, err := e.driver.Get(k)
+	if err != nil {
+		return Head{}, err
+	}
+	return Head{Size: int64(len(v)), Sum: crc32.ChecksumIEEE(v)}, nil
+}
@@ -0,0 +1,133 @@
+package acdb
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/godump/doa"
+)
+
+// LfuDriver implemention. Least frequently used (LFU), counts how often an item is needed. Those that are used least
+// often are discarded first, items with the same frequency are discarded in least recently used order. It has a fixed
+// size and O(1) time lookup.
+type LfuDriver struct {
+	data  map[string]*lfuEntry
+	freq  map[int]*list.List
+	least int
+	size  int
+	m     *sync.Mutex
+}
+
+type lfuEntry struct {
+	k string
+	v []byte
+	n int
+	e *list.Element
+}
+
+// NewLfuDriver returns a LfuDriver. The size must be at least 1.
+func NewLfuDriver(size int) *LfuDriver {
+	doa.Doa(size >= 1)
+	return &LfuDriver{
+		data: map[string]*lfuEntry{},
+		freq: map[int]*list.List{},
+		size: size,
+		m:    &sync.Mutex{},
+	}
+}
+
+// bump increments the frequency of an entry.
+func (d *LfuDriver) bump(e *lfuEntry) {
+	l := d.freq[e.n]
+	l.Remove(e.e)
+	if l.Len() == 0 {
+		delete(d.freq, e.n)
+		if d.least == e.n {
+			d.least++
+		}
+	}
+	e.n++
+	d.push(e)
+}
+
+// push puts an entry at the front of the list of its frequency.
+func (d *LfuDriver) push(e *lfuEntry) {
+	l, b := d.freq[e.n]
+	if !b {
+		l = list.New()
+		d.freq[e.n] = l
+	}
+	e.e = l.PushFront(e)
+}
+
+// drop removes an entry.
+func (d *LfuDriver) drop(e *lfuEntry) {
+	l := d.freq[e.n]
+	l.Remove(e.e)
+	if l.Len() == 0 {
+		delete(d.freq, e.n)
+	}
+	delete(d.data, e.k)
+}
+
+// Get the value of a key.
+func (d *LfuDriver) Get(k string) ([]byte, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, b := d.data[k]
+	if !b {
+		return nil, ErrNotExist
+	}
+	d.bump(e)
+	return e.v, nil
+}
+
+// Set the value of a key.
+func (d *LfuDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if e, b := d.data[k]; b {
+		e.v = v
+		d.bump(e)
+		return nil
+	}
+	if len(d.data) >= d.size {
+		d.drop(d.freq[d.least].Back().Value.(*lfuEntry))
+	}
+	e := &lfuEntry{k: k, v: v, n: 1}
+	d.data[k] = e
+	d.push(e)
+	d.least = 1
+	return nil
+}
+
+// Del the value of a key.
+func (d *LfuDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, b := d.data[k]
+	if !b {
+		return ErrNotExist
+	}
+	d.drop(e)
+	return nil
+}
+
+// Scan calls f for each key and its value.
+func (d *LfuDriver) Scan(f func(k string, v []byte) error) error {
+	d.m.Lock()
+	l := make([]lfuEntry, 0, len(d.data))
+	for _, e := range d.data {
+		l = append(l, lfuEntry{k: e.k, v: e.v})
+	}
+	d.m.Unlock()
+	for _, e := range l {
+		if err := f(e.k, e.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lfu returns a concurrency-safety Client with LfuDriver.
+func Lfu(size int) *Client { return NewClient(NewLfuDriver(size)) }
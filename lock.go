@@ -0,0 +1,45 @@
+package acdb
+
+import (
+	"os"
+)
+
+// Lock locks the root directory against other processes. A writer takes an exclusive lock and readers take a shared
+// lock, so a root is either used by a single writer or by any number of readers. If the lock is held by another
+// process in a conflicting mode, Lock fails immediately with ErrLocked. The lock is advisory: it only guards against
+// other processes which lock the same root.
+func (d *DocDriver) Lock(write bool) error {
+	if d.lock != nil {
+		return nil
+	}
+	f, err := os.Open(d.root)
+	if err != nil {
+		return err
+	}
+	if err := flock(f, write); err != nil {
+		f.Close()
+		return err
+	}
+	d.lock = f
+	return nil
+}
+
+// Unlock releases the lock taken by Lock.
+func (d *DocDriver) Unlock() error {
+	if d.lock == nil {
+		return nil
+	}
+	err := d.lock.Close()
+	d.lock = nil
+	return err
+}
+
+// Lock locks the root directory against other processes, see DocDriver.Lock.
+func (d *MapDriver) Lock(write bool) error {
+	return d.doc.Lock(write)
+}
+
+// Unlock releases the lock taken by Lock.
+func (d *MapDriver) Unlock() error {
+	return d.doc.Unlock()
+}
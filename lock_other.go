@@ -0,0 +1,12 @@
+//go:build !unix
+
+package acdb
+
+import (
+	"os"
+)
+
+// flock is not supported on this platform.
+func flock(f *os.File, write bool) error {
+	return ErrUnsupported
+}
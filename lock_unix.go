@@ -0,0 +1,22 @@
+//go:build unix
+
+package acdb
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// flock applies a non-blocking advisory lock on f.
+func flock(f *os.File, write bool) error {
+	how := syscall.LOCK_SH
+	if write {
+		how = syscall.LOCK_EX
+	}
+	err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+	return err
+}
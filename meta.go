@@ -0,0 +1,151 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Meta describes the history of a key.
+type Meta struct {
+	// Created is the time the key was first set.
+	Created time.Time
+	// Updated is the time the key was last set.
+	Updated time.Time
+	// Version starts at 1 and is incremented by every set.
+	Version uint64
+}
+
+// MetaReader is implemented by drivers which keep metadata for their keys.
+type MetaReader interface {
+	Meta(k string) (Meta, error)
+}
+
+// metaMagic starts every metadata header, so values stored before the driver was wrapped can be told apart.
+const metaMagic = "\x00acm"
+
+// metaSize is the size of the metadata header.
+const metaSize = 28
+
+// MetaDriver wraps a driver and stores a small header with each value, holding its creation time, update time and
+// version. The header is stripped on Get, so callers see the plain value.
+//
+// An existing store can be wrapped: values without a header are returned as they are, Meta reports ErrNoMeta for them,
+// and the next Set adds a header with version 1, created at that time.
+type MetaDriver struct {
+	inner Driver
+	m     *sync.Mutex
+}
+
+// NewMetaDriver returns a MetaDriver.
+func NewMetaDriver(inner Driver) *MetaDriver {
+	return &MetaDriver{inner: inner, m: &sync.Mutex{}}
+}
+
+// split separates the header from a stored value. A value without a header is returned whole, with ErrNoMeta.
+func (d *MetaDriver) split(b []byte) (Meta, []byte, error) {
+	if len(b) < metaSize || string(b[:4]) != metaMagic {
+		return Meta{}, b, ErrNoMeta
+	}
+	return Meta{
+		Created: time.Unix(0, int64(binary.BigEndian.Uint64(b[4:12]))),
+		Updated: time.Unix(0, int64(binary.BigEndian.Uint64(b[12:20]))),
+		Version: binary.BigEndian.Uint64(b[20:28]),
+	}, b[metaSize:], nil
+}
+
+// Get the value of a key.
+func (d *MetaDriver) Get(k string) ([]byte, error) {
+	b, err := d.inner.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	_, v, _ := d.split(b)
+	return v, nil
+}
+
+// Meta returns the metadata of a key.
+func (d *MetaDriver) Meta(k string) (Meta, error) {
+	b, err := d.inner.Get(k)
+	if err != nil {
+		return Meta{}, err
+	}
+	m, _, err := d.split(b)
+	return m, err
+}
+
+// Set the value of a key. The creation time is kept and the version is incremented.
+func (d *MetaDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	n := time.Now()
+	m := Meta{Created: n}
+	b, err := d.inner.Get(k)
+	if err == nil {
+		if o, _, err := d.split(b); err == nil {
+			m = o
+		}
+	} else if !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	m.Updated = n
+	m.Version++
+	b = make([]byte, metaSize+len(v))
+	copy(b, metaMagic)
+	binary.BigEndian.PutUint64(b[4:12], uint64(m.Created.UnixNano()))
+	binary.BigEndian.PutUint64(b[12:20], uint64(m.Updated.UnixNano()))
+	binary.BigEndian.PutUint64(b[20:28], m.Version)
+	copy(b[metaSize:], v)
+	return d.inner.Set(k, b)
+}
+
+// Del the value of a key.
+func (d *MetaDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.inner.Del(k)
+}
+
+// Scan calls f for each key and its value. The inner driver must implement Scanner.
+func (d *MetaDriver) Scan(f func(k string, v []byte) error) error {
+	s, b := d.inner.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(func(k string, b []byte) error {
+		_, v, _ := d.split(b)
+		return f(k, v)
+	})
+}
+
+// Meta returns the metadata of a key. The driver must implement MetaReader.
+func (e *Client) Meta(k string) (Meta, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	m, b := e.driver.(MetaReader)
+	if !b {
+		return Meta{}, ErrUnsupported
+	}
+	return m.Meta(e.resolve(k))
+}
+
+// SetIfVersion sets the value of a key only if its current version equals version, implementing optimistic locking.
+// A version of 0 means the key must not exist. Otherwise ErrConflict is returned. The driver must implement
+// MetaReader.
+func (e *Client) SetIfVersion(k string, v []byte, version uint64) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	r, b := e.driver.(MetaReader)
+	if !b {
+		return ErrUnsupported
+	}
+	m, err := r.Meta(k)
+	if err != nil && !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	if m.Version != version {
+		return ErrConflict
+	}
+	return e.set(k, v)
+}
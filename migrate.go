@@ -0,0 +1,79 @@
+package acdb
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// MigrateOption controls the behavior of Migrate.
+type MigrateOption struct {
+	// Rate limits the number of keys copied per second. Zero means no limit.
+	Rate int
+	// Verify reads every key back from the destination and compares it with the source.
+	Verify bool
+	// Progress is called after every copied key with the report so far.
+	Progress func(r MigrateReport)
+	// DryRun reports what would be copied without writing anything to the destination.
+	DryRun bool
+}
+
+// MigrateReport describes the keys handled by Migrate.
+type MigrateReport struct {
+	Keys int
+	Size int64
+	// Sample holds the first few keys handled.
+	Sample []string
+}
+
+// Migrate copies all keys from src to dst. The src driver must implement Scanner. A nil option copies as fast as
+// possible without verification. In dry-run mode, the returned report describes what would have been copied.
+func Migrate(src Driver, dst Driver, opt *MigrateOption) (MigrateReport, error) {
+	r := MigrateReport{}
+	s, b := src.(Scanner)
+	if !b {
+		return r, ErrUnsupported
+	}
+	if opt == nil {
+		opt = &MigrateOption{}
+	}
+	t := time.Now()
+	err := s.Scan(func(k string, v []byte) error {
+		if !opt.DryRun {
+			if err := migrate(dst, k, v, opt, t, r.Keys); err != nil {
+				return err
+			}
+		}
+		if len(r.Sample) < 8 {
+			r.Sample = append(r.Sample, k)
+		}
+		r.Keys++
+		r.Size += int64(len(v))
+		if opt.Progress != nil {
+			opt.Progress(r)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// migrate copies the n-th key to dst.
+func migrate(dst Driver, k string, v []byte, opt *MigrateOption, t time.Time, n int) error {
+	if opt.Rate > 0 {
+		time.Sleep(time.Until(t.Add(time.Duration(n) * time.Second / time.Duration(opt.Rate))))
+	}
+	if err := dst.Set(k, v); err != nil {
+		return err
+	}
+	if !opt.Verify {
+		return nil
+	}
+	buf, err := dst.Get(k)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(buf, v) {
+		return fmt.Errorf("acdb: verify %s failed", k)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package acdb
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// mirrorOp is a command to replay against the shadow driver.
+type mirrorOp struct {
+	op string
+	k  string
+	v  []byte
+}
+
+// MirrorDriver wraps a driver and asynchronously replays a sample of its commands against a shadow driver, for load
+// testing a new version with real workloads. Only commands are mirrored, results of the shadow are discarded, and
+// commands are dropped rather than slowing down the inner driver when the shadow falls behind.
+type MirrorDriver struct {
+	inner   Driver
+	shadow  Driver
+	rate    float64
+	redact  func(k string, v []byte) (string, []byte)
+	queue   chan mirrorOp
+	dropped uint64
+	done    chan struct{}
+}
+
+// NewMirrorDriver returns a MirrorDriver which mirrors the given fraction of commands, between 0 and 1, to shadow.
+func NewMirrorDriver(inner Driver, shadow Driver, rate float64) *MirrorDriver {
+	d := &MirrorDriver{
+		inner:  inner,
+		shadow: shadow,
+		rate:   rate,
+		queue:  make(chan mirrorOp, 1024),
+		done:   make(chan struct{}),
+	}
+	go d.loop()
+	return d
+}
+
+// loop replays queued commands until the driver is closed.
+func (d *MirrorDriver) loop() {
+	defer close(d.done)
+	for o := range d.queue {
+		switch o.op {
+		case "get":
+			d.shadow.Get(o.k)
+		case "set":
+			d.shadow.Set(o.k, o.v)
+		case "del":
+			d.shadow.Del(o.k)
+		}
+	}
+}
+
+// Redact sets a function which rewrites keys and values before they are sent to the shadow, for example to remove
+// personal data. It must be called before the driver is used.
+func (d *MirrorDriver) Redact(f func(k string, v []byte) (string, []byte)) {
+	d.redact = f
+}
+
+// Dropped returns the number of sampled commands dropped because the shadow fell behind.
+func (d *MirrorDriver) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// mirror queues a command if it is sampled.
+func (d *MirrorDriver) mirror(op string, k string, v []byte) {
+	if rand.Float64() >= d.rate {
+		return
+	}
+	if d.redact != nil {
+		k, v = d.redact(k, v)
+	}
+	select {
+	case d.queue <- mirrorOp{op: op, k: k, v: v}:
+	default:
+		atomic.AddUint64(&d.dropped, 1)
+	}
+}
+
+// Get the value of a key.
+func (d *MirrorDriver) Get(k string) ([]byte, error) {
+	d.mirror("get", k, nil)
+	return d.inner.Get(k)
+}
+
+// Set the value of a key.
+func (d *MirrorDriver) Set(k string, v []byte) error {
+	d.mirror("set", k, v)
+	return d.inner.Set(k, v)
+}
+
+// Del the value of a key.
+func (d *MirrorDriver) Del(k string) error {
+	d.mirror("del", k, nil)
+	return d.inner.Del(k)
+}
+
+// Close stops mirroring after the queued commands are replayed. The driver must not be used afterwards.
+func (d *MirrorDriver) Close() error {
+	close(d.queue)
+	<-d.done
+	return nil
+}
@@ -0,0 +1,39 @@
+package acdb
+
+import (
+	"io"
+	"os"
+)
+
+// Mmap makes the driver read values of at least min bytes through a memory mapping of their file and copy them out,
+// instead of going through the buffers of read calls. A min of zero or less disables it, and platforms without mmap
+// read normally. A file truncated by another process while it is mapped crashes the process, so the root must only be
+// written through this driver.
+func (d *DocDriver) Mmap(min int64) {
+	d.mmap = min
+}
+
+// read returns the content of a file.
+func (d *DocDriver) read(name string) ([]byte, error) {
+	if d.mmap <= 0 {
+		return os.ReadFile(name)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	i, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if i.Size() < d.mmap {
+		return io.ReadAll(f)
+	}
+	return mmap(f, i.Size())
+}
+
+// Mmap makes large values be read from the file system through memory mappings, see DocDriver.Mmap.
+func (d *MapDriver) Mmap(min int64) {
+	d.doc.Mmap(min)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package acdb
+
+import (
+	"io"
+	"os"
+)
+
+// mmap is not supported on this platform, f is read normally.
+func mmap(f *os.File, n int64) ([]byte, error) {
+	return io.ReadAll(f)
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package acdb
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap returns a copy of the first n bytes of f, read through a memory mapping.
+func mmap(f *os.File, n int64) ([]byte, error) {
+	if n == 0 {
+		return []byte{}, nil
+	}
+	m, err := syscall.Mmap(int(f.Fd()), 0, int(n), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	copy(b, m)
+	return b, syscall.Munmap(m)
+}
@@ -0,0 +1,71 @@
+package acdb
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// lockPrefix is the key prefix under which the state of named locks is stored in the driver.
+const lockPrefix = "acdb.lock."
+
+// lockState is the value stored in the key of a named lock.
+type lockState struct {
+	Fence uint64 `json:"fence"`
+	Until int64  `json:"until"`
+}
+
+// Lock acquires the named lock for at most ttl. If the lock is held by someone else and has not expired, ErrLocked is
+// returned immediately. The returned function releases the lock. The state of the lock is stored under the key
+// "acdb.lock." + name, apart from the data keys.
+func (e *Client) Lock(name string, ttl time.Duration) (func(), error) {
+	_, unlock, err := e.LockFence(name, ttl)
+	return unlock, err
+}
+
+// LockFence acquires the named lock like Lock and also returns its fencing token. Tokens strictly increase each time
+// the lock is acquired, so a resource guarded by the lock can reject writes carrying an older token from a holder
+// whose lock has expired.
+func (e *Client) LockFence(name string, ttl time.Duration) (uint64, func(), error) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	s := lockState{}
+	b, err := e.driver.Get(lockPrefix + name)
+	if err == nil {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, nil, err
+		}
+		if time.Now().UnixNano() < s.Until {
+			return 0, nil, ErrLocked
+		}
+	} else if !errors.Is(err, ErrNotExist) {
+		return 0, nil, err
+	}
+	s.Fence++
+	s.Until = time.Now().Add(ttl).UnixNano()
+	b, err = json.Marshal(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := e.set(lockPrefix+name, b); err != nil {
+		return 0, nil, err
+	}
+	fence := s.Fence
+	return fence, func() { e.unlock(name, fence) }, nil
+}
+
+// unlock releases the named lock if it is still held with the given fencing token. The key is kept, with an expired
+// deadline, so that the next token continues from this one.
+func (e *Client) unlock(name string, fence uint64) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	s := lockState{}
+	b, err := e.driver.Get(lockPrefix + name)
+	if err != nil || json.Unmarshal(b, &s) != nil || s.Fence != fence {
+		return
+	}
+	s.Until = 0
+	if b, err := json.Marshal(s); err == nil {
+		e.set(lockPrefix+name, b)
+	}
+}
@@ -0,0 +1,15 @@
+package acdb
+
+import (
+	"time"
+
+	"github.com/godump/lru"
+)
+
+// Negative makes the driver remember, for ttl, up to size keys which were looked up and found missing on the file
+// system, so that repeated lookups of them return ErrNotExist without touching the disk. Keys written through the
+// driver are forgotten at once, keys written by other processes only after ttl or when Watch sees them. It must be
+// called before the driver is used.
+func (d *MapDriver) Negative(size int, ttl time.Duration) {
+	d.neg = lru.New[string, struct{}](size, ttl)
+}
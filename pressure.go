@@ -0,0 +1,44 @@
+package acdb
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// Pressure returns the memory used by the go runtime as a fraction of the limit configured by debug.SetMemoryLimit or
+// GOMEMLIMIT. If no limit is configured, it returns 0.
+func Pressure() float64 {
+	l := debug.SetMemoryLimit(-1)
+	if l <= 0 || l == math.MaxInt64 {
+		return 0
+	}
+	s := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s)
+	return float64(s[0].Value.Uint64()-s[1].Value.Uint64()) / float64(l)
+}
+
+// OnPressure checks Pressure every interval and calls f each time it is greater than ratio. Memory-bound drivers can
+// be shrunk in f before the process is killed for running out of memory. It returns a function that stops checking.
+func OnPressure(ratio float64, interval time.Duration, f func()) func() {
+	t := time.NewTicker(interval)
+	c := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				if Pressure() > ratio {
+					f()
+				}
+			case <-c:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(c) }
+}
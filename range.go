@@ -0,0 +1,115 @@
+package acdb
+
+import (
+	"errors"
+	"sort"
+)
+
+// Ranger is implemented by drivers which are able to list keys in order.
+//
+// Range returns the keys in [start, end) with their values in ascending order of keys, at most limit of them. An
+// empty end means no upper bound and a limit of zero or less means no limit.
+type Ranger interface {
+	Range(start string, end string, limit int) ([]KV, error)
+}
+
+// inRange reports whether the i-th key k is still wanted by a range query.
+func inRange(k string, end string, limit int, i int) bool {
+	return (end == "" || k < end) && (limit <= 0 || i < limit)
+}
+
+// index returns the keys in ascending order. The sorted index is built on demand and dropped whenever a key is added
+// or removed, so writes stay O(1) and only the first range after them pays for sorting. The returned slice is never
+// modified.
+func (d *MemDriver) index() []string {
+	d.m.RLock()
+	l := d.keys
+	d.m.RUnlock()
+	if l != nil {
+		return l
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.keys == nil {
+		d.keys = make([]string, 0, len(d.data))
+		for k := range d.data {
+			d.keys = append(d.keys, k)
+		}
+		sort.Strings(d.keys)
+	}
+	return d.keys
+}
+
+// Range returns the keys in [start, end) with their values in ascending order. Keys are taken from a sorted index.
+func (d *MemDriver) Range(start string, end string, limit int) ([]KV, error) {
+	l := d.index()
+	d.m.RLock()
+	defer d.m.RUnlock()
+	r := []KV{}
+	for i := sort.SearchStrings(l, start); i < len(l) && inRange(l[i], end, limit, len(r)); i++ {
+		v, b := d.data[l[i]]
+		if !b {
+			continue
+		}
+		r = append(r, KV{K: l[i], V: v})
+	}
+	return r, nil
+}
+
+// Range returns the keys in [start, end) with their values in ascending order. Only the files in range are read.
+func (d *DocDriver) Range(start string, end string, limit int) ([]KV, error) {
+	l, err := d.list()
+	if err != nil {
+		return nil, err
+	}
+	r := []KV{}
+	i := sort.Search(len(l), func(i int) bool { return l[i].Name() >= start })
+	for ; i < len(l) && inRange(l[i].Name(), end, limit, len(r)); i++ {
+		v, err := d.Get(l[i].Name())
+		if errors.Is(err, ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, KV{K: l[i].Name(), V: v})
+	}
+	return r, nil
+}
+
+// Range returns the keys in [start, end) with their values in ascending order. The values are read from the file
+// system, after flushing pending writes.
+func (d *MapDriver) Range(start string, end string, limit int) ([]KV, error) {
+	if err := d.Flush(); err != nil {
+		return nil, err
+	}
+	return d.doc.Range(start, end, limit)
+}
+
+// Range returns the keys in [start, end) with their values in ascending order, at most limit of them. Drivers which
+// do not implement Ranger but implement Scanner are scanned entirely.
+func (e *Client) Range(start string, end string, limit int) ([]KV, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	switch d := e.driver.(type) {
+	case Ranger:
+		return d.Range(start, end, limit)
+	case Scanner:
+		r := []KV{}
+		err := d.Scan(func(k string, v []byte) error {
+			if k >= start && (end == "" || k < end) {
+				r = append(r, KV{K: k, V: v})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(r, func(i, j int) bool { return r[i].K < r[j].K })
+		if limit > 0 && len(r) > limit {
+			r = r[:limit]
+		}
+		return r, nil
+	}
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,36 @@
+package acdb
+
+// ReadonlyDriver wraps a driver and rejects every write with ErrReadOnly, so replicas or stores under maintenance can
+// serve reads safely.
+type ReadonlyDriver struct {
+	inner Driver
+}
+
+// NewReadonlyDriver returns a ReadonlyDriver.
+func NewReadonlyDriver(inner Driver) *ReadonlyDriver {
+	return &ReadonlyDriver{inner: inner}
+}
+
+// Get the value of a key.
+func (d *ReadonlyDriver) Get(k string) ([]byte, error) {
+	return d.inner.Get(k)
+}
+
+// Set always returns ErrReadOnly.
+func (d *ReadonlyDriver) Set(k string, v []byte) error {
+	return ErrReadOnly
+}
+
+// Del always returns ErrReadOnly.
+func (d *ReadonlyDriver) Del(k string) error {
+	return ErrReadOnly
+}
+
+// Scan calls f for each key and its value. The inner driver must implement Scanner.
+func (d *ReadonlyDriver) Scan(f func(k string, v []byte) error) error {
+	s, b := d.inner.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(f)
+}
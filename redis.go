@@ -0,0 +1,296 @@
+package acdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/godump/doa"
+)
+
+// RedisDriver stores data in a remote Redis or Valkey server. It speaks the RESP protocol over a single connection,
+// which is dialed again after any network error. Dialing and each command time out after 5 seconds by default, see
+// Timeout.
+type RedisDriver struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	conn     net.Conn
+	rd       *bufio.Reader
+	m        *sync.Mutex
+}
+
+// NewRedisDriver returns a RedisDriver. An empty password skips authentication.
+func NewRedisDriver(addr string, password string, db int) *RedisDriver {
+	d := &RedisDriver{
+		addr:     addr,
+		password: password,
+		db:       db,
+		timeout:  5 * time.Second,
+		m:        &sync.Mutex{},
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	doa.Nil(d.dial())
+	return d
+}
+
+// Timeout sets the time allowed to dial the server and to send each command and read its reply. A zero timeout waits
+// forever.
+func (d *RedisDriver) Timeout(t time.Duration) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.timeout = t
+}
+
+// dial connects to the server, authenticates and selects the database. The caller must hold the lock.
+func (d *RedisDriver) dial() error {
+	c, err := net.DialTimeout("tcp", d.addr, d.timeout)
+	if err != nil {
+		return err
+	}
+	d.conn = c
+	d.rd = bufio.NewReader(c)
+	if d.password != "" {
+		if _, err := d.exec("AUTH", d.password); err != nil {
+			d.shut()
+			return err
+		}
+	}
+	if d.db != 0 {
+		if _, err := d.exec("SELECT", strconv.Itoa(d.db)); err != nil {
+			d.shut()
+			return err
+		}
+	}
+	return nil
+}
+
+// shut closes the connection. The caller must hold the lock.
+func (d *RedisDriver) shut() {
+	d.conn.Close()
+	d.conn = nil
+	d.rd = nil
+}
+
+// exec sends a command and reads its reply. The caller must hold the lock.
+func (d *RedisDriver) exec(args ...string) (interface{}, error) {
+	b := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		b = append(b, "$"+strconv.Itoa(len(a))+"\r\n"+a+"\r\n"...)
+	}
+	t := time.Time{}
+	if d.timeout > 0 {
+		t = time.Now().Add(d.timeout)
+	}
+	if err := d.conn.SetDeadline(t); err != nil {
+		return nil, err
+	}
+	if _, err := d.conn.Write(b); err != nil {
+		return nil, err
+	}
+	return d.read()
+}
+
+// read reads a reply. Replies sent by the server as errors are returned as RedisError.
+func (d *RedisDriver) read() (interface{}, error) {
+	l, err := d.rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(l) < 3 || l[len(l)-2] != '\r' {
+		return nil, errors.New("acdb: malformed redis reply")
+	}
+	t, s := l[0], l[1:len(l)-2]
+	switch t {
+	case '+':
+		return s, nil
+	case '-':
+		return nil, RedisError(s)
+	case ':':
+		return strconv.ParseInt(s, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(d.rd, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		r := make([]interface{}, n)
+		for i := range r {
+			if r[i], err = d.read(); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("acdb: unknown redis reply type %q", t)
+}
+
+// call sends a command, reconnecting first if the previous connection was broken.
+func (d *RedisDriver) call(args ...string) (interface{}, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.conn == nil {
+		if err := d.dial(); err != nil {
+			return nil, err
+		}
+	}
+	r, err := d.exec(args...)
+	if err != nil && !errors.As(err, new(RedisError)) {
+		d.shut()
+	}
+	return r, err
+}
+
+// errRedisReply is returned when a reply is not of the type expected for the command.
+var errRedisReply = errors.New("acdb: unexpected redis reply")
+
+// redisBytes returns a bulk string reply.
+func redisBytes(r interface{}) ([]byte, error) {
+	b, ok := r.([]byte)
+	if !ok {
+		return nil, errRedisReply
+	}
+	return b, nil
+}
+
+// redisInt returns an integer reply.
+func redisInt(r interface{}) (int64, error) {
+	n, ok := r.(int64)
+	if !ok {
+		return 0, errRedisReply
+	}
+	return n, nil
+}
+
+// RedisError is an error reply sent by the redis server.
+type RedisError string
+
+// Error implements the error interface.
+func (e RedisError) Error() string {
+	return "acdb: redis: " + string(e)
+}
+
+// Get the value of a key.
+func (d *RedisDriver) Get(k string) ([]byte, error) {
+	r, err := d.call("GET", k)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ErrNotExist
+	}
+	return redisBytes(r)
+}
+
+// Set the value of a key.
+func (d *RedisDriver) Set(k string, v []byte) error {
+	_, err := d.call("SET", k, string(v))
+	return err
+}
+
+// Del the value of a key.
+func (d *RedisDriver) Del(k string) error {
+	r, err := d.call("DEL", k)
+	if err != nil {
+		return err
+	}
+	n, err := redisInt(r)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotExist
+	}
+	return nil
+}
+
+// Add increments the integer value of a key by n and returns the new value. The value is stored as decimal text, as
+// redis INCRBY does, so it can't be shared with Client.Counter, which stores 8 binary bytes.
+func (d *RedisDriver) Add(k string, n int64) (int64, error) {
+	r, err := d.call("INCRBY", k, strconv.FormatInt(n, 10))
+	if err != nil {
+		return 0, err
+	}
+	return redisInt(r)
+}
+
+// Dec decrements the integer value of a key by n and returns the new value.
+func (d *RedisDriver) Dec(k string, n int64) (int64, error) {
+	r, err := d.call("DECRBY", k, strconv.FormatInt(n, 10))
+	if err != nil {
+		return 0, err
+	}
+	return redisInt(r)
+}
+
+// Scan calls f for each key and its value. Keys are listed with SCAN, so keys changed during the scan may or may not
+// be visited.
+func (d *RedisDriver) Scan(f func(k string, v []byte) error) error {
+	c := "0"
+	for {
+		r, err := d.call("SCAN", c, "COUNT", "128")
+		if err != nil {
+			return err
+		}
+		l, ok := r.([]interface{})
+		if !ok || len(l) != 2 {
+			return errRedisReply
+		}
+		b, err := redisBytes(l[0])
+		if err != nil {
+			return err
+		}
+		c = string(b)
+		m, ok := l[1].([]interface{})
+		if !ok {
+			return errRedisReply
+		}
+		for _, e := range m {
+			b, err := redisBytes(e)
+			if err != nil {
+				return err
+			}
+			k := string(b)
+			v, err := d.Get(k)
+			if errors.Is(err, ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := f(k, v); err != nil {
+				return err
+			}
+		}
+		if c == "0" {
+			return nil
+		}
+	}
+}
+
+// Redis returns a concurrency-safety Client with RedisDriver.
+func Redis(addr string, password string, db int) *Client {
+	return NewClient(NewRedisDriver(addr, password, db))
+}
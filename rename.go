@@ -0,0 +1,110 @@
+package acdb
+
+import (
+	"errors"
+	"os"
+	"path"
+)
+
+// Renamer is implemented by drivers which are able to move a value to another key without reading it.
+//
+// Rename moves the value of src to dst, replacing any value of dst. If src does not exist, ErrNotExist will be
+// returned.
+type Renamer interface {
+	Rename(src string, dst string) error
+}
+
+// Rename moves the value of src to dst by renaming its file.
+func (d *DocDriver) Rename(src string, dst string) error {
+	if d.shard {
+		if err := os.MkdirAll(path.Dir(d.path(dst)), 0755); err != nil {
+			return err
+		}
+	}
+	err := os.Rename(d.path(src), d.path(dst))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	}
+	if err != nil {
+		return err
+	}
+	if err := d.sync(path.Dir(d.path(src))); err != nil {
+		return err
+	}
+	return d.sync(d.path(dst))
+}
+
+// Rename moves the value of src to dst by renaming its file, after flushing pending writes. Both keys are dropped from
+// the cache.
+func (d *MapDriver) Rename(src string, dst string) error {
+	if err := d.Flush(); err != nil {
+		return err
+	}
+	if d.bloom != nil {
+		d.bloom.add(dst)
+	}
+	if d.neg != nil {
+		d.neg.Del(dst)
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err := d.doc.Rename(src, dst); err != nil {
+		return err
+	}
+	for _, k := range []string{src, dst} {
+		if err := d.lru.Del(k); err != nil && !errors.Is(err, ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy sets the value of dst to the value of src, atomically. Any value of dst is replaced.
+func (e *Client) Copy(src string, dst string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	v, err := e.driver.Get(src)
+	if err != nil {
+		return err
+	}
+	return e.set(dst, v)
+}
+
+// Rename moves the value of src to dst, atomically. Any value of dst is replaced. If the driver implements Renamer and
+// no validators or set observers are registered, the value is moved without being read. Renaming a key to itself
+// leaves it unchanged.
+func (e *Client) Rename(src string, dst string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if src == dst {
+		_, err := e.driver.Get(src)
+		return err
+	}
+	r, b := e.driver.(Renamer)
+	if !b || len(e.onset) != 0 || len(e.after) != 0 {
+		v, err := e.driver.Get(src)
+		if err != nil {
+			return err
+		}
+		if err := e.set(dst, v); err != nil {
+			return err
+		}
+		return e.del(src)
+	}
+	if e.maxk > 0 && len(dst) > e.maxk {
+		return ErrTooLarge
+	}
+	e.access(dst)
+	if err := r.Rename(src, dst); err != nil {
+		return err
+	}
+	e.notify(dst)
+	if e.tags {
+		e.untag(src)
+	}
+	e.notify(src)
+	for _, f := range e.afterd {
+		f(src)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package acdb
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ReplicaDriver sends writes to a primary driver and spreads reads over read replicas in turn, typically RedisDrivers
+// of a primary server and its replicas. When a replica fails, the next one is tried, and the primary last. Replicas
+// may lag behind the primary, so a read right after a write may see the old value.
+type ReplicaDriver struct {
+	primary  Driver
+	replicas []Driver
+	n        uint64
+}
+
+// NewReplicaDriver returns a ReplicaDriver. Without replicas, all reads go to the primary.
+func NewReplicaDriver(primary Driver, replicas ...Driver) *ReplicaDriver {
+	return &ReplicaDriver{primary: primary, replicas: replicas}
+}
+
+// Get the value of a key from a replica.
+func (d *ReplicaDriver) Get(k string) ([]byte, error) {
+	if len(d.replicas) == 0 {
+		return d.primary.Get(k)
+	}
+	i := int(atomic.AddUint64(&d.n, 1) % uint64(len(d.replicas)))
+	for j := 0; j < len(d.replicas); j++ {
+		v, err := d.replicas[(i+j)%len(d.replicas)].Get(k)
+		if err == nil || errors.Is(err, ErrNotExist) {
+			return v, err
+		}
+	}
+	return d.primary.Get(k)
+}
+
+// Set the value of a key on the primary.
+func (d *ReplicaDriver) Set(k string, v []byte) error {
+	return d.primary.Set(k, v)
+}
+
+// Del the value of a key on the primary.
+func (d *ReplicaDriver) Del(k string) error {
+	return d.primary.Del(k)
+}
+
+// Scan calls f for each key and its value of the primary, which must implement Scanner.
+func (d *ReplicaDriver) Scan(f func(k string, v []byte) error) error {
+	s, b := d.primary.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(f)
+}
@@ -0,0 +1,151 @@
+package acdb
+
+import (
+	"errors"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ringPoint is a virtual node on the hash ring.
+type ringPoint struct {
+	h uint32
+	n string
+}
+
+// RingDriver shards keys across several drivers, typically RedisDrivers on different servers, with a consistent hash
+// ring. Adding or removing a node only moves the keys between it and its neighbours on the ring. When the node owning
+// a key fails, the next nodes on the ring are tried in turn.
+type RingDriver struct {
+	m     *sync.RWMutex
+	nodes map[string]Driver
+	ring  []ringPoint
+}
+
+// NewRingDriver returns a RingDriver without nodes.
+func NewRingDriver() *RingDriver {
+	return &RingDriver{m: &sync.RWMutex{}, nodes: map[string]Driver{}}
+}
+
+// ringHash returns the position of a string on the ring.
+func ringHash(s string) uint32 {
+	return crc32.ChecksumIEEE([]byte(s))
+}
+
+// build rebuilds the ring from the nodes. The caller must hold the lock.
+func (d *RingDriver) build() {
+	d.ring = d.ring[:0]
+	for n := range d.nodes {
+		for i := 0; i < 160; i++ {
+			d.ring = append(d.ring, ringPoint{h: ringHash(n + "#" + strconv.Itoa(i)), n: n})
+		}
+	}
+	sort.Slice(d.ring, func(i, j int) bool {
+		if d.ring[i].h != d.ring[j].h {
+			return d.ring[i].h < d.ring[j].h
+		}
+		return d.ring[i].n < d.ring[j].n
+	})
+}
+
+// Add adds a node under a name. The name, not the driver, decides which keys the node owns, so it must stay the same
+// across restarts, for example the address of the server.
+func (d *RingDriver) Add(name string, n Driver) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.nodes[name] = n
+	d.build()
+}
+
+// Remove removes a node. Its keys are not moved, they become unreachable unless still stored on the new owner.
+func (d *RingDriver) Remove(name string) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	delete(d.nodes, name)
+	d.build()
+}
+
+// lookup returns the nodes for a key, the owner first, then the others in ring order.
+func (d *RingDriver) lookup(k string) []Driver {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	r := []Driver{}
+	if len(d.ring) == 0 {
+		return r
+	}
+	h := ringHash(k)
+	i := sort.Search(len(d.ring), func(i int) bool { return d.ring[i].h >= h })
+	seen := map[string]bool{}
+	for j := 0; j < len(d.ring) && len(r) < len(d.nodes); j++ {
+		p := d.ring[(i+j)%len(d.ring)]
+		if !seen[p.n] {
+			seen[p.n] = true
+			r = append(r, d.nodes[p.n])
+		}
+	}
+	return r
+}
+
+// try calls f with the nodes of a key in turn until one succeeds or reports that the key does not exist.
+func (d *RingDriver) try(k string, f func(n Driver) error) error {
+	err := errors.New("acdb: no nodes")
+	for _, n := range d.lookup(k) {
+		err = f(n)
+		if err == nil || errors.Is(err, ErrNotExist) {
+			return err
+		}
+	}
+	return err
+}
+
+// Get the value of a key.
+func (d *RingDriver) Get(k string) ([]byte, error) {
+	var r []byte
+	err := d.try(k, func(n Driver) error {
+		v, err := n.Get(k)
+		r = v
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Set the value of a key.
+func (d *RingDriver) Set(k string, v []byte) error {
+	return d.try(k, func(n Driver) error { return n.Set(k, v) })
+}
+
+// Del the value of a key.
+func (d *RingDriver) Del(k string) error {
+	return d.try(k, func(n Driver) error { return n.Del(k) })
+}
+
+// Scan calls f for each key and its value. Every node must implement Scanner. Keys stored on a node which does not own
+// them, after a failover or a change of nodes, are skipped.
+func (d *RingDriver) Scan(f func(k string, v []byte) error) error {
+	d.m.RLock()
+	l := make([]Driver, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		l = append(l, n)
+	}
+	d.m.RUnlock()
+	for _, n := range l {
+		s, b := n.(Scanner)
+		if !b {
+			return ErrUnsupported
+		}
+		err := s.Scan(func(k string, v []byte) error {
+			if o := d.lookup(k); len(o) == 0 || o[0] != n {
+				return nil
+			}
+			return f(k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
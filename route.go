@@ -0,0 +1,79 @@
+package acdb
+
+import (
+	"strings"
+)
+
+// RouteDriver dispatches each key to a driver chosen by the longest matching key prefix, so a single store can mix
+// durability requirements, for example "cache/" on a LruDriver and "durable/" on a DocDriver. Keys matching no prefix
+// go to the default driver.
+type RouteDriver struct {
+	def    Driver
+	routes map[string]Driver
+}
+
+// NewRouteDriver returns a RouteDriver with the given default driver.
+func NewRouteDriver(def Driver) *RouteDriver {
+	return &RouteDriver{def: def, routes: map[string]Driver{}}
+}
+
+// Route sends keys under prefix to d. It must be called before the driver is used.
+func (d *RouteDriver) Route(prefix string, r Driver) {
+	d.routes[prefix] = r
+}
+
+// route returns the driver for a key.
+func (d *RouteDriver) route(k string) Driver {
+	r, n := d.def, -1
+	for p, e := range d.routes {
+		if len(p) > n && strings.HasPrefix(k, p) {
+			r, n = e, len(p)
+		}
+	}
+	return r
+}
+
+// Get the value of a key.
+func (d *RouteDriver) Get(k string) ([]byte, error) {
+	return d.route(k).Get(k)
+}
+
+// Set the value of a key.
+func (d *RouteDriver) Set(k string, v []byte) error {
+	return d.route(k).Set(k, v)
+}
+
+// Del the value of a key.
+func (d *RouteDriver) Del(k string) error {
+	return d.route(k).Del(k)
+}
+
+// Scan calls f for each key and its value. Every routed driver must implement Scanner. Keys stored in a driver they
+// are not routed to are skipped.
+func (d *RouteDriver) Scan(f func(k string, v []byte) error) error {
+	l := []Driver{d.def}
+	for _, e := range d.routes {
+		l = append(l, e)
+	}
+	seen := map[Driver]bool{}
+	for _, e := range l {
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		s, b := e.(Scanner)
+		if !b {
+			return ErrUnsupported
+		}
+		err := s.Scan(func(k string, v []byte) error {
+			if d.route(k) != e {
+				return nil
+			}
+			return f(k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
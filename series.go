@@ -0,0 +1,143 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/godump/doa"
+)
+
+// Point is a sample of a Series.
+type Point struct {
+	T time.Time
+	V float64
+}
+
+// Series is a time series of float64 samples. Samples are stored in chunks, one per span of time, each holding 16
+// bytes per sample: the unix nanoseconds and the bits of the value, both big-endian. A chunk is split in parts of a
+// bounded number of samples, the first named name.<unix seconds of the start of the span>, the next ones suffixed with
+// .1, .2 and so on. Append reads and rewrites the last part, so its cost is bounded by the part size rather than by the
+// number of samples in the span.
+type Series struct {
+	client *Client
+	name   string
+	span   time.Duration
+	size   int
+	pc     int64
+	pi     int
+}
+
+// Series returns the Series stored under a name. Chunks span one hour by default and parts hold 1024 samples.
+func (e *Client) Series(name string) *Series {
+	return &Series{client: e, name: name, span: time.Hour, size: 1024, pc: math.MinInt64}
+}
+
+// Span sets the time covered by each chunk, for example 24 hours for data which is sampled rarely. It must be the same
+// every time the series is opened. Chunks are named after seconds, so the span must be a whole number of seconds.
+func (s *Series) Span(d time.Duration) *Series {
+	doa.Doa(d >= time.Second && d%time.Second == 0)
+	s.span = d
+	return s
+}
+
+// Size sets the maximum number of samples of each part of a chunk. Smaller parts make appends cheaper and ranges read
+// more keys. It must be the same every time the series is opened.
+func (s *Series) Size(n int) *Series {
+	doa.Doa(n >= 1)
+	s.size = n
+	return s
+}
+
+// chunk returns the start of the chunk containing t in unix nanoseconds.
+func (s *Series) chunk(t int64) int64 {
+	n := int64(s.span)
+	if t < 0 {
+		return (t - n + 1) / n * n
+	}
+	return t / n * n
+}
+
+// key returns the key of the i-th part of the chunk starting at t.
+func (s *Series) key(t int64, i int) string {
+	k := s.name + "." + strconv.FormatInt(t/int64(time.Second), 10)
+	if i == 0 {
+		return k
+	}
+	return k + "." + strconv.Itoa(i)
+}
+
+// Append records a sample. The last part of the chunk written is remembered, so appending to the current chunk does not
+// read its full parts again.
+func (s *Series) Append(t time.Time, v float64) error {
+	s.client.m.Lock()
+	defer s.client.m.Unlock()
+	n := t.UnixNano()
+	c := s.chunk(n)
+	i := 0
+	if c == s.pc {
+		i = s.pi
+	}
+	var b []byte
+	for j := i; ; {
+		p, err := s.client.driver.Get(s.key(c, i))
+		if errors.Is(err, ErrNotExist) && i == j && i != 0 {
+			i, j = 0, 0
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrNotExist) {
+			return err
+		}
+		if len(p) < s.size*16 {
+			b = p
+			break
+		}
+		i++
+	}
+	p := make([]byte, len(b)+16)
+	copy(p, b)
+	binary.BigEndian.PutUint64(p[len(b):], uint64(n))
+	binary.BigEndian.PutUint64(p[len(b)+8:], math.Float64bits(v))
+	if err := s.client.set(s.key(c, i), p); err != nil {
+		return err
+	}
+	s.pc = c
+	s.pi = i
+	return nil
+}
+
+// Range returns the samples in [from, to) in ascending order of time. Every chunk in between is read, so the range
+// should not be much wider than the data recorded.
+func (s *Series) Range(from time.Time, to time.Time) ([]Point, error) {
+	s.client.m.RLock()
+	defer s.client.m.RUnlock()
+	r := []Point{}
+	a := from.UnixNano()
+	z := to.UnixNano()
+	for c := s.chunk(a); c < z; c += int64(s.span) {
+		for j := 0; ; j++ {
+			b, err := s.client.driver.Get(s.key(c, j))
+			if errors.Is(err, ErrNotExist) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(b)%16 != 0 {
+				return nil, errors.New("acdb: value is not a series chunk")
+			}
+			for i := 0; i < len(b); i += 16 {
+				n := int64(binary.BigEndian.Uint64(b[i:]))
+				if n < a || n >= z {
+					continue
+				}
+				r = append(r, Point{T: time.Unix(0, n), V: math.Float64frombits(binary.BigEndian.Uint64(b[i+8:]))})
+			}
+		}
+	}
+	sort.SliceStable(r, func(i, j int) bool { return r[i].T.Before(r[j].T) })
+	return r, nil
+}
@@ -0,0 +1,145 @@
+package acdb
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// members returns the members of the set stored in a key. A key which does not exist is an empty set. The caller
+// must hold the lock.
+func (e *Client) members(k string) (map[string]struct{}, error) {
+	l := []string{}
+	b, err := e.driver.Get(k)
+	if errors.Is(err, ErrNotExist) {
+		return map[string]struct{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, err
+	}
+	r := make(map[string]struct{}, len(l))
+	for _, m := range l {
+		r[m] = struct{}{}
+	}
+	return r, nil
+}
+
+// sorted returns the members of a set in ascending order.
+func sorted(s map[string]struct{}) []string {
+	r := make([]string, 0, len(s))
+	for m := range s {
+		r = append(r, m)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// setMembers stores a set as a sorted json array. An empty set deletes the key. The caller must hold the lock.
+func (e *Client) setMembers(k string, s map[string]struct{}) error {
+	if len(s) == 0 {
+		err := e.del(k)
+		if errors.Is(err, ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	b, err := json.Marshal(sorted(s))
+	if err != nil {
+		return err
+	}
+	return e.set(k, b)
+}
+
+// SAdd adds members to the set stored in a key, creating the set if needed.
+func (e *Client) SAdd(k string, m ...string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	s, err := e.members(k)
+	if err != nil {
+		return err
+	}
+	for _, x := range m {
+		s[x] = struct{}{}
+	}
+	return e.setMembers(k, s)
+}
+
+// SRem removes members from the set stored in a key. The key is deleted along with its last member.
+func (e *Client) SRem(k string, m ...string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	s, err := e.members(k)
+	if err != nil {
+		return err
+	}
+	for _, x := range m {
+		delete(s, x)
+	}
+	return e.setMembers(k, s)
+}
+
+// SIsMember determine if m is a member of the set stored in a key.
+func (e *Client) SIsMember(k string, m string) (bool, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	s, err := e.members(e.resolve(k))
+	if err != nil {
+		return false, err
+	}
+	_, b := s[m]
+	return b, nil
+}
+
+// SMembers returns the members of the set stored in a key in ascending order.
+func (e *Client) SMembers(k string) ([]string, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	s, err := e.members(e.resolve(k))
+	if err != nil {
+		return nil, err
+	}
+	return sorted(s), nil
+}
+
+// SUnion returns the members of any of the sets stored in the given keys in ascending order.
+func (e *Client) SUnion(k ...string) ([]string, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	r := map[string]struct{}{}
+	for _, x := range k {
+		s, err := e.members(e.resolve(x))
+		if err != nil {
+			return nil, err
+		}
+		for m := range s {
+			r[m] = struct{}{}
+		}
+	}
+	return sorted(r), nil
+}
+
+// SInter returns the members of all of the sets stored in the given keys in ascending order.
+func (e *Client) SInter(k ...string) ([]string, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	r := map[string]struct{}{}
+	for i, x := range k {
+		s, err := e.members(e.resolve(x))
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			r = s
+			continue
+		}
+		for m := range r {
+			if _, b := s[m]; !b {
+				delete(r, m)
+			}
+		}
+	}
+	return sorted(r), nil
+}
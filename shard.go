@@ -0,0 +1,145 @@
+package acdb
+
+import (
+	"hash/fnv"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// path returns the file name of a key.
+func (d *DocDriver) path(k string) string {
+	if !d.shard {
+		return path.Join(d.root, k)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(k))
+	s := strconv.FormatUint(uint64(h.Sum32())|1<<32, 16)[1:]
+	return path.Join(d.root, s[0:2], s[2:4], k)
+}
+
+// isShard reports whether a directory name is a shard directory.
+func isShard(e os.DirEntry) bool {
+	n := e.Name()
+	if !e.IsDir() || len(n) != 2 {
+		return false
+	}
+	_, err := strconv.ParseUint(n, 16, 8)
+	return err == nil
+}
+
+// list returns the files of all keys, sorted by key.
+func (d *DocDriver) list() ([]os.DirEntry, error) {
+	l, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil, err
+	}
+	r := []os.DirEntry{}
+	if !d.shard {
+		for _, e := range l {
+			if e.Type().IsRegular() {
+				r = append(r, e)
+			}
+		}
+		return r, nil
+	}
+	for _, a := range l {
+		if !isShard(a) {
+			continue
+		}
+		m, err := os.ReadDir(path.Join(d.root, a.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range m {
+			if !isShard(b) {
+				continue
+			}
+			n, err := os.ReadDir(path.Join(d.root, a.Name(), b.Name()))
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range n {
+				if e.Type().IsRegular() {
+					r = append(r, e)
+				}
+			}
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Name() < r[j].Name() })
+	return r, nil
+}
+
+// Shard switches the driver to a fan-out directory layout, storing each key in root/ab/cd/key where abcd comes from a
+// hash of the key, so that no directory grows too large. Keys stored in the flat layout are moved into place. It must
+// be called before the driver is used, and every time a driver is opened on the same root.
+//
+// Keys are first linked into a fresh tree under root/.shard, leaving the flat layout untouched until every key is
+// there, then the flat files are removed and the tree is moved into place. If it fails after the flat files are
+// removed, the keys wait under root/.shard and calling Shard again completes the migration.
+func (d *DocDriver) Shard() error {
+	l, err := d.list()
+	if err != nil {
+		return err
+	}
+	s := &DocDriver{root: path.Join(d.root, ".shard"), shard: true}
+	done := []string{}
+	for _, e := range l {
+		if err := link(path.Join(d.root, e.Name()), s.path(e.Name())); err != nil {
+			for _, p := range done {
+				os.Remove(p)
+			}
+			return err
+		}
+		done = append(done, s.path(e.Name()))
+	}
+	for _, e := range l {
+		if err := os.Remove(path.Join(d.root, e.Name())); err != nil {
+			return err
+		}
+	}
+	m, err := s.list()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	t := &DocDriver{root: d.root, shard: true}
+	for _, e := range m {
+		p := t.path(e.Name())
+		if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(s.path(e.Name()), p); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(s.root); err != nil {
+		return err
+	}
+	d.shard = true
+	return nil
+}
+
+// link makes dst another name of the file src, creating the directory of dst. The file is copied where hard links
+// are not supported.
+func link(src string, dst string) error {
+	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if os.Link(src, dst) == nil {
+		return nil
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, 0644)
+}
+
+// Shard switches the file system layout to fan-out directories, see DocDriver.Shard.
+func (d *MapDriver) Shard() error {
+	return d.doc.Shard()
+}
@@ -0,0 +1,154 @@
+package acdb
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// SignDriver wraps a driver and verifies detached ed25519 signatures on read. The signature of key k is stored in key
+// k + ".sig", next to the value. It covers the key as well as the value, so a signed value copied to another key with
+// its signature is rejected. Keys under a prefix for which public keys are trusted are only returned when their
+// signature verifies with one of those keys, so consumers can check provenance even if the store itself is
+// compromised. Keys under no trusted prefix are returned as is. Signature keys are hidden from Scan, Range and Stats,
+// so keys ending with ".sig" can't be used for values.
+type SignDriver struct {
+	inner Driver
+	trust map[string][]ed25519.PublicKey
+}
+
+// NewSignDriver returns a SignDriver.
+func NewSignDriver(inner Driver) *SignDriver {
+	return &SignDriver{
+		inner: inner,
+		trust: map[string][]ed25519.PublicKey{},
+	}
+}
+
+// Trust accepts signatures made with pub for all keys under prefix. It must be called before the driver is used.
+func (d *SignDriver) Trust(prefix string, pub ed25519.PublicKey) {
+	d.trust[prefix] = append(d.trust[prefix], pub)
+}
+
+// signMessage returns the message signed for the value v of key k: a domain prefix, the length of the key, the key and
+// the value.
+func signMessage(k string, v []byte) []byte {
+	m := []byte("acdb.sign.v1\x00")
+	m = binary.BigEndian.AppendUint64(m, uint64(len(k)))
+	m = append(m, k...)
+	return append(m, v...)
+}
+
+// Get the value of a key. If the key is under a trusted prefix, the value is returned only if its signature verifies.
+func (d *SignDriver) Get(k string) ([]byte, error) {
+	v, err := d.inner.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	l := []ed25519.PublicKey{}
+	for p, e := range d.trust {
+		if strings.HasPrefix(k, p) {
+			l = append(l, e...)
+		}
+	}
+	if len(l) == 0 {
+		return v, nil
+	}
+	s, err := d.inner.Get(k + ".sig")
+	if errors.Is(err, ErrNotExist) {
+		return nil, ErrSignature
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := signMessage(k, v)
+	for _, e := range l {
+		if ed25519.Verify(e, m, s) {
+			return v, nil
+		}
+	}
+	return nil, ErrSignature
+}
+
+// Set the value of a key. The value is stored unsigned, use SetSigned to sign it.
+func (d *SignDriver) Set(k string, v []byte) error {
+	return d.inner.Set(k, v)
+}
+
+// SetSigned signs the value with priv and sets both the value and its signature.
+func (d *SignDriver) SetSigned(k string, v []byte, priv ed25519.PrivateKey) error {
+	if err := d.inner.Set(k+".sig", ed25519.Sign(priv, signMessage(k, v))); err != nil {
+		return err
+	}
+	return d.inner.Set(k, v)
+}
+
+// Del the value of a key and its signature.
+func (d *SignDriver) Del(k string) error {
+	if err := d.inner.Del(k + ".sig"); err != nil && !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	return d.inner.Del(k)
+}
+
+// isSig reports whether k is the key of a signature.
+func isSig(k string) bool {
+	return strings.HasSuffix(k, ".sig")
+}
+
+// Scan calls f for each key and its value, skipping signatures. The inner driver must implement Scanner.
+func (d *SignDriver) Scan(f func(k string, v []byte) error) error {
+	s, b := d.inner.(Scanner)
+	if !b {
+		return ErrUnsupported
+	}
+	return s.Scan(func(k string, v []byte) error {
+		if isSig(k) {
+			return nil
+		}
+		return f(k, v)
+	})
+}
+
+// Range returns the keys in [start, end) and their values in ascending order, skipping signatures. The inner driver
+// must implement Ranger.
+func (d *SignDriver) Range(start string, end string, limit int) ([]KV, error) {
+	g, b := d.inner.(Ranger)
+	if !b {
+		return nil, ErrUnsupported
+	}
+	r := []KV{}
+	for {
+		l, err := g.Range(start, end, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range l {
+			if !isSig(e.K) && inRange(e.K, end, limit, len(r)) {
+				r = append(r, e)
+			}
+		}
+		if limit <= 0 || len(l) < limit || len(r) >= limit {
+			return r, nil
+		}
+		start = l[len(l)-1].K + "\x00"
+	}
+}
+
+// Stats returns statistics of the inner driver, with the keys and size of signatures left out. Keys and size are
+// counted by scanning the inner driver, so they are zero if it does not implement Scanner.
+func (d *SignDriver) Stats() Stats {
+	s := Stats{}
+	if t, b := d.inner.(Stater); b {
+		s = t.Stats()
+	}
+	s.Keys = 0
+	s.Size = 0
+	d.Scan(func(k string, v []byte) error {
+		s.Keys++
+		s.Size += int64(len(v))
+		return nil
+	})
+	return s
+}
@@ -0,0 +1,194 @@
+package acdb
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/godump/doa"
+)
+
+// SnapDriver wraps a memory driver such as MemDriver or LruDriver and periodically writes all of its data to a
+// snapshot file, which is loaded again when the driver is created. This is a middle ground between pure memory and
+// per-write durability: after a crash, the writes since the last snapshot are lost. The inner driver must implement
+// Scanner.
+//
+// Snapshots triggered by the interval or by the number of writes are written by a background goroutine, so Set and Del
+// return without waiting for them, and their failures are recorded rather than returned, see Err. Reads and writes
+// made while a snapshot is being written wait for it.
+type SnapDriver struct {
+	inner  Driver
+	name   string
+	writes int
+	n      int
+	snap   chan struct{}
+	m      *sync.Mutex
+	err    error
+	up     chan struct{}
+	upd    chan struct{}
+	uperr  error
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewSnapDriver returns a SnapDriver. A snapshot is written every interval and every given number of writes, whichever
+// comes first. A zero interval or writes disables the corresponding trigger.
+func NewSnapDriver(inner Driver, name string, interval time.Duration, writes int) *SnapDriver {
+	_, b := inner.(Scanner)
+	doa.Doa(b)
+	d := &SnapDriver{
+		inner:  inner,
+		name:   name,
+		writes: writes,
+		snap:   make(chan struct{}, 1),
+		m:      &sync.Mutex{},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	f, err := os.Open(name)
+	if err == nil {
+		defer f.Close()
+		doa.Nil(Load(f, inner))
+	} else {
+		doa.Doa(os.IsNotExist(err))
+	}
+	go d.loop(interval)
+	return d
+}
+
+// loop writes a snapshot every interval, and whenever enough writes happened, until the driver is closed.
+func (d *SnapDriver) loop(interval time.Duration) {
+	defer close(d.done)
+	var c <-chan time.Time
+	if interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		c = t.C
+	}
+	for {
+		select {
+		case <-c:
+		case <-d.snap:
+		case <-d.stop:
+			return
+		}
+		d.m.Lock()
+		if d.n != 0 {
+			d.err = d.snapshot()
+		}
+		d.m.Unlock()
+	}
+}
+
+// snapshot atomically replaces the snapshot file with the current data. The caller must hold the lock.
+func (d *SnapDriver) snapshot() error {
+	f, err := os.Create(d.name + ".tmp")
+	if err != nil {
+		return err
+	}
+	if err := Dump(d.inner.(Scanner), f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(d.name+".tmp", d.name); err != nil {
+		return err
+	}
+	d.n = 0
+	if d.up != nil {
+		select {
+		case d.up <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Snapshot writes a snapshot immediately.
+func (d *SnapDriver) Snapshot() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.snapshot()
+}
+
+// Err returns the error of the last snapshot triggered by the interval or by the number of writes, or nil if it
+// succeeded.
+func (d *SnapDriver) Err() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.err
+}
+
+// wrote counts a write and asks for a snapshot if enough writes happened. The caller must hold the lock.
+func (d *SnapDriver) wrote() {
+	d.n++
+	if d.writes > 0 && d.n >= d.writes {
+		select {
+		case d.snap <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Get the value of a key.
+func (d *SnapDriver) Get(k string) ([]byte, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.inner.Get(k)
+}
+
+// Set the value of a key.
+func (d *SnapDriver) Set(k string, v []byte) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err := d.inner.Set(k, v); err != nil {
+		return err
+	}
+	d.wrote()
+	return nil
+}
+
+// Del the value of a key.
+func (d *SnapDriver) Del(k string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err := d.inner.Del(k); err != nil {
+		return err
+	}
+	d.wrote()
+	return nil
+}
+
+// Scan calls f for each key and its value.
+func (d *SnapDriver) Scan(f func(k string, v []byte) error) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.inner.(Scanner).Scan(f)
+}
+
+// Close stops the periodic snapshots, writes a final one and waits for it to be uploaded. Closing again only writes
+// another snapshot.
+func (d *SnapDriver) Close() error {
+	d.once.Do(func() { close(d.stop) })
+	<-d.done
+	err := d.Snapshot()
+	d.m.Lock()
+	c := d.upd
+	if d.up != nil {
+		close(d.up)
+		d.up = nil
+	}
+	d.m.Unlock()
+	if err != nil || c == nil {
+		return err
+	}
+	<-c
+	return d.UploadErr()
+}
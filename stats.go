@@ -0,0 +1,105 @@
+package acdb
+
+import (
+	"time"
+)
+
+// Stats describes the content of a driver and, for caches, how effective it is.
+type Stats struct {
+	// Keys is the number of keys.
+	Keys int
+	// Size is the approximate total size of all values in bytes.
+	Size int64
+	// Hit and Miss count cache lookups which were and were not found in the cache.
+	Hit  uint64
+	Miss uint64
+	// Evict counts entries discarded to make room for new ones.
+	Evict uint64
+}
+
+// HitRatio returns the fraction of lookups which were found in the cache.
+func (s Stats) HitRatio() float64 {
+	if s.Hit+s.Miss == 0 {
+		return 0
+	}
+	return float64(s.Hit) / float64(s.Hit+s.Miss)
+}
+
+// Stater is implemented by drivers which are able to describe themselves cheaply.
+type Stater interface {
+	Stats() Stats
+}
+
+// Stats returns statistics of the driver.
+func (d *MemDriver) Stats() Stats {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	s := Stats{Keys: len(d.data)}
+	for _, v := range d.data {
+		s.Size += int64(len(v))
+	}
+	return s
+}
+
+// Stats returns statistics of the driver. Sizes are taken from the file system without reading any file.
+func (d *DocDriver) Stats() Stats {
+	s := Stats{}
+	l, err := d.list()
+	if err != nil {
+		return s
+	}
+	for _, e := range l {
+		i, err := e.Info()
+		if err != nil {
+			continue
+		}
+		s.Keys++
+		s.Size += i.Size()
+	}
+	return s
+}
+
+// Stats returns statistics of the driver. Expired entries which were not discarded yet are not counted.
+func (d *LruDriver) Stats() Stats {
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	s := Stats{Hit: d.hit, Miss: d.miss, Evict: d.evict}
+	for e := d.data.List.Root.Next; e != &d.data.List.Root; e = e.Next {
+		if time.Since(e.U) <= d.data.E {
+			s.Keys++
+			s.Size += int64(len(e.V))
+		}
+	}
+	return s
+}
+
+// Stats returns statistics of the driver. Keys and sizes come from the file system, hits, misses and evictions from
+// the cache.
+func (d *MapDriver) Stats() Stats {
+	s := d.doc.Stats()
+	c := d.lru.Stats()
+	s.Hit = c.Hit
+	s.Miss = c.Miss
+	s.Evict = c.Evict
+	return s
+}
+
+// Stats returns statistics of the driver. If the driver implements neither Stater nor Scanner, the zero Stats is
+// returned.
+func (e *Client) Stats() Stats {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	switch d := e.driver.(type) {
+	case Stater:
+		return d.Stats()
+	case Scanner:
+		s := Stats{}
+		d.Scan(func(k string, v []byte) error {
+			s.Keys++
+			s.Size += int64(len(v))
+			return nil
+		})
+		return s
+	}
+	return Stats{}
+}
@@ -0,0 +1,234 @@
+package acdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path"
+)
+
+// StreamGetter is implemented by drivers which are able to return a value as a stream, without holding all of it in
+// memory.
+//
+// GetReader returns a reader of the value of a key, which the caller must close. If the key does not exist,
+// ErrNotExist will be returned.
+type StreamGetter interface {
+	GetReader(k string) (io.ReadCloser, error)
+}
+
+// GetReader returns a reader of the value of a key.
+func (d *MemDriver) GetReader(k string) (io.ReadCloser, error) {
+	v, err := d.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(v)), nil
+}
+
+// GetReader returns the opened file of a key. With checksums enabled, the value is read and verified first.
+func (d *DocDriver) GetReader(k string) (io.ReadCloser, error) {
+	if d.sum {
+		v, err := d.Get(k)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(v)), nil
+	}
+	f, err := os.Open(d.path(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return f, err
+}
+
+// GetReader returns a reader of the value of a key. Cached values are read from memory, others are streamed from the
+// file system without being cached.
+func (d *MapDriver) GetReader(k string) (io.ReadCloser, error) {
+	if d.wb != nil {
+		if e, b := d.wb.get(k); b {
+			if e.del {
+				return nil, ErrNotExist
+			}
+			return io.NopCloser(bytes.NewReader(e.v)), nil
+		}
+	}
+	if v, err := d.lru.Get(k); err == nil {
+		return io.NopCloser(bytes.NewReader(v)), nil
+	}
+	if d.bloom != nil && !d.bloom.has(k) {
+		return nil, ErrNotExist
+	}
+	if d.neg != nil {
+		if _, b := d.neg.GetExists(k); b {
+			return nil, ErrNotExist
+		}
+	}
+	return d.doc.GetReader(k)
+}
+
+// GetReader returns a reader of the value of a key, which the caller must close. If the driver does not implement
+// StreamGetter, the value is read entirely first. The stream is read after the client is unlocked, so a concurrent
+// write may be seen partially.
+func (e *Client) GetReader(k string) (io.ReadCloser, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	k = e.resolve(k)
+	e.access(k)
+	if s, b := e.driver.(StreamGetter); b {
+		return s.GetReader(k)
+	}
+	v, err := e.driver.Get(k)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(v)), nil
+}
+
+// StreamSetter is implemented by drivers which are able to store a value from a stream, without holding all of it in
+// memory.
+//
+// SetReader sets the value of a key to the content read from r, which must be exactly size bytes long, or of any
+// length if size is negative.
+type StreamSetter interface {
+	SetReader(k string, r io.Reader, size int64) error
+}
+
+// readSize reads a value of exactly size bytes from r, or all of r if size is negative.
+func readSize(r io.Reader, size int64) ([]byte, error) {
+	if size < 0 {
+		return io.ReadAll(r)
+	}
+	v := make([]byte, size)
+	if _, err := io.ReadFull(r, v); err != nil {
+		return nil, err
+	}
+	if n, _ := io.CopyN(io.Discard, r, 1); n != 0 {
+		return nil, errors.New("acdb: stream longer than its size")
+	}
+	return v, nil
+}
+
+// limitReader reads from r and fails with ErrTooLarge after n bytes.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader.
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// SetReader sets the value of a key to the content read from r. The content is streamed to a temporary file which
+// replaces the file of the key once complete, so readers never see a partial value.
+func (d *DocDriver) SetReader(k string, r io.Reader, size int64) error {
+	t := path.Join(d.root, ".tmp")
+	if err := os.MkdirAll(t, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(t, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	h := crc32.NewIEEE()
+	if size >= 0 {
+		r = io.LimitReader(r, size+1)
+	}
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if err == nil && size >= 0 && n < size {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && size >= 0 && n > size {
+		err = errors.New("acdb: stream longer than its size")
+	}
+	if err == nil && d.sum {
+		err = binary.Write(f, binary.BigEndian, h.Sum32())
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := d.sync(f.Name()); err != nil {
+		return err
+	}
+	if d.shard {
+		if err := os.MkdirAll(path.Dir(d.path(k)), 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(f.Name(), d.path(k)); err != nil {
+		return err
+	}
+	return d.sync(d.path(k))
+}
+
+// SetReader sets the value of a key to the content read from r. The content is streamed to the file system, pending
+// writes are flushed first, and the value is not cached.
+func (d *MapDriver) SetReader(k string, r io.Reader, size int64) error {
+	if err := d.Flush(); err != nil {
+		return err
+	}
+	if d.bloom != nil {
+		d.bloom.add(k)
+	}
+	if d.neg != nil {
+		d.neg.Del(k)
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err := d.doc.SetReader(k, r, size); err != nil {
+		return err
+	}
+	if err := d.lru.Del(k); err != nil && !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SetReader sets the value of a key to the content read from r, which must be exactly size bytes long, or of any
+// length if size is negative. If the driver does not implement StreamSetter, or validators are registered with OnSet,
+// the value is read into memory first, so memory drivers should be protected with a value limit, see Limit. Values
+// over the limit are rejected with ErrTooLarge.
+func (e *Client) SetReader(k string, r io.Reader, size int64) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if (e.maxk > 0 && len(k) > e.maxk) || (e.maxv > 0 && size > int64(e.maxv)) {
+		return ErrTooLarge
+	}
+	if e.maxv > 0 && size < 0 {
+		r = &limitReader{r: r, n: int64(e.maxv)}
+	}
+	s, b := e.driver.(StreamSetter)
+	if !b || len(e.onset) != 0 {
+		v, err := readSize(r, size)
+		if err != nil {
+			return err
+		}
+		return e.set(k, v)
+	}
+	if e.log != 0 {
+		log.Println("acdb: set", k, size, "bytes")
+	}
+	e.access(k)
+	if err := s.SetReader(k, r, size); err != nil {
+		return err
+	}
+	e.notify(k)
+	for _, f := range e.after {
+		f(k, nil)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package acdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Key prefixes of the tag index stored in the driver: the keys of each tag, the tags of each key, and the deadline of
+// each tag set by ExpireTag.
+const (
+	tagPrefix    = "acdb.tag."
+	keyTagPrefix = "acdb.keytags."
+	tagExpPrefix = "acdb.tagexp."
+)
+
+// putIndex stores a set of the tag index as a sorted json array, deleting it when empty. Index keys bypass validators
+// and observers. The caller must hold the lock.
+func (e *Client) putIndex(k string, s map[string]struct{}) error {
+	if len(s) == 0 {
+		err := e.driver.Del(k)
+		if errors.Is(err, ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	b, err := json.Marshal(sorted(s))
+	if err != nil {
+		return err
+	}
+	return e.driver.Set(k, b)
+}
+
+// Tag attaches tags to a key, so the key can later be listed, deleted or expired by tag. The index is stored in the
+// driver under keys prefixed with "acdb.tag.", "acdb.keytags." and "acdb.tagexp.", so it is shared by every client of
+// the same store and survives restarts. Deleting a key through a Client detaches all its tags, once enabled with Tags,
+// which Tag does for its own client.
+func (e *Client) Tag(k string, tags ...string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.tags = true
+	kt, err := e.members(keyTagPrefix + k)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if err := e.expireTag(t); err != nil {
+			return err
+		}
+		s, err := e.members(tagPrefix + t)
+		if err != nil {
+			return err
+		}
+		s[k] = struct{}{}
+		if err := e.putIndex(tagPrefix+t, s); err != nil {
+			return err
+		}
+		kt[t] = struct{}{}
+	}
+	return e.putIndex(keyTagPrefix+k, kt)
+}
+
+// Tags enables or disables detaching the tags of keys deleted or renamed through the client. Detaching costs one more
+// driver lookup per delete, so it is disabled by default; every client deleting tagged keys should enable it. Keys
+// deleted without detaching their tags are dropped from the index by the next Tagged of each tag, unless they were set
+// again meanwhile.
+func (e *Client) Tags(b bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.tags = b
+}
+
+// untag detaches all tags from a key. The caller must hold the lock. Failures when called after a delete are ignored,
+// as Tagged drops keys which no longer exist.
+func (e *Client) untag(k string) error {
+	kt, err := e.members(keyTagPrefix + k)
+	if err != nil || len(kt) == 0 {
+		return err
+	}
+	for t := range kt {
+		s, err := e.members(tagPrefix + t)
+		if err != nil {
+			return err
+		}
+		delete(s, k)
+		if err := e.putIndex(tagPrefix+t, s); err != nil {
+			return err
+		}
+	}
+	return e.putIndex(keyTagPrefix+k, nil)
+}
+
+// Tagged returns the keys with the given tag in ascending order. Keys which no longer exist are detached.
+func (e *Client) Tagged(tag string) ([]string, error) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if err := e.expireTag(tag); err != nil {
+		return nil, err
+	}
+	s, err := e.members(tagPrefix + tag)
+	if err != nil {
+		return nil, err
+	}
+	n := len(s)
+	for k := range s {
+		_, err := e.driver.Get(k)
+		if errors.Is(err, ErrNotExist) {
+			if err := e.untag(k); err != nil {
+				return nil, err
+			}
+			delete(s, k)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(s) != n {
+		if err := e.putIndex(tagPrefix+tag, s); err != nil {
+			return nil, err
+		}
+	}
+	return sorted(s), nil
+}
+
+// DelTag dels all keys with the given tag, for example to invalidate everything tagged "user:42".
+func (e *Client) DelTag(tag string) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return e.delTag(tag)
+}
+
+// delTag dels all keys with the given tag and forgets its deadline. The caller must hold the lock.
+func (e *Client) delTag(tag string) error {
+	s, err := e.members(tagPrefix + tag)
+	if err != nil {
+		return err
+	}
+	for k := range s {
+		if err := e.del(k); err != nil && !errors.Is(err, ErrNotExist) {
+			return err
+		}
+		if err := e.untag(k); err != nil {
+			return err
+		}
+	}
+	if err := e.driver.Del(tagExpPrefix + tag); err != nil && !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// expireTag dels the keys of a tag if its deadline has passed. The caller must hold the lock.
+func (e *Client) expireTag(tag string) error {
+	b, err := e.driver.Get(tagExpPrefix + tag)
+	if errors.Is(err, ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(b) != 8 || time.Now().UnixNano() < int64(binary.BigEndian.Uint64(b)) {
+		return nil
+	}
+	return e.delTag(tag)
+}
+
+// ExpireTag dels all keys with the given tag after ttl. The deadline is stored with the tag index: this client dels
+// the keys when it comes, and any client enforces an expired deadline on its next Tag or Tagged of the tag, so the
+// expiry also holds across restarts. Keys tagged after the call expire along with the others.
+func (e *Client) ExpireTag(tag string, ttl time.Duration) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Add(ttl).UnixNano()))
+	if err := e.driver.Set(tagExpPrefix+tag, b); err != nil {
+		return err
+	}
+	time.AfterFunc(ttl, func() {
+		e.m.Lock()
+		defer e.m.Unlock()
+		e.expireTag(tag)
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+package acdb
+
+import (
+	"time"
+)
+
+// Toucher is implemented by drivers which are able to keep a key alive without returning its value.
+//
+// Touch marks a key as just used and restarts its time to live. If the key does not exist, ErrNotExist will be
+// returned.
+type Toucher interface {
+	Touch(k string) error
+}
+
+// Touch moves a key to the front of the cache and restarts its time to live.
+func (d *LruDriver) Touch(k string) error {
+	d.data.M.Lock()
+	defer d.data.M.Unlock()
+	e, b := d.data.C[k]
+	if !b {
+		return ErrNotExist
+	}
+	if time.Since(e.U) > d.data.E {
+		d.remove(e)
+		return ErrNotExist
+	}
+	d.data.List.Move(e, &d.data.List.Root)
+	d.stamp(e)
+	return nil
+}
+
+// Touch keeps a key in the cache. A key which is not cached is loaded from the file system.
+func (d *MapDriver) Touch(k string) error {
+	if err := d.lru.Touch(k); err == nil {
+		return nil
+	}
+	_, err := d.Get(k)
+	return err
+}
+
+// Touch marks a key as just used, restarting its time to live on drivers whose keys expire, without returning its
+// value. If the driver does not implement Toucher, only the existence of the key is checked.
+func (e *Client) Touch(k string) error {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	k = e.resolve(k)
+	e.access(k)
+	if t, b := e.driver.(Toucher); b {
+		return t.Touch(k)
+	}
+	_, err := e.driver.Get(k)
+	return err
+}
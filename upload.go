@@ -0,0 +1,114 @@
+package acdb
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+// Uploader copies snapshot files to another place, such as a backup directory or an object store, so that data
+// survives the loss of the machine.
+type Uploader interface {
+	// Upload stores the content read from r under a name. Names of later snapshots sort after earlier ones.
+	Upload(name string, r io.Reader) error
+}
+
+// DirUploader is an Uploader which copies snapshots into a local directory, typically a mounted network or backup
+// volume, keeping only the most recent ones.
+type DirUploader struct {
+	root string
+	keep int
+}
+
+// NewDirUploader returns a DirUploader. The directory should be dedicated to the snapshots of one driver, as all but
+// the last keep files in it are removed after each upload. A keep of zero or less keeps everything.
+func NewDirUploader(root string, keep int) *DirUploader {
+	return &DirUploader{root: root, keep: keep}
+}
+
+// Upload copies r into the directory, then removes old snapshots.
+func (u *DirUploader) Upload(name string, r io.Reader) error {
+	f, err := os.CreateTemp(u.root, ".upload")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path.Join(u.root, name)); err != nil {
+		return err
+	}
+	if u.keep <= 0 {
+		return nil
+	}
+	l, err := os.ReadDir(u.root)
+	if err != nil {
+		return err
+	}
+	s := []string{}
+	for _, e := range l {
+		if e.Type().IsRegular() && e.Name()[0] != '.' {
+			s = append(s, e.Name())
+		}
+	}
+	sort.Strings(s)
+	for i := 0; i < len(s)-u.keep; i++ {
+		if err := os.Remove(path.Join(u.root, s[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UploadTo makes the driver pass every snapshot it writes to u, named after the snapshot file and the time it was
+// uploaded. Uploads run in the background, outside of the driver lock, so a slow uploader never delays reads and
+// writes. When snapshots are written faster than they are uploaded, only the latest is uploaded. Upload failures are
+// recorded, see UploadErr, the local snapshot is kept anyway. It must be called at most once, before the driver is
+// used.
+func (d *SnapDriver) UploadTo(u Uploader) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.up = make(chan struct{}, 1)
+	d.upd = make(chan struct{})
+	go d.uploadLoop(u, d.up)
+}
+
+// UploadErr returns the error of the last upload, or nil if it succeeded.
+func (d *SnapDriver) UploadErr() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.uperr
+}
+
+// uploadLoop uploads the snapshot file each time it is signaled on c, until c is closed.
+func (d *SnapDriver) uploadLoop(u Uploader, c chan struct{}) {
+	defer close(d.upd)
+	for range c {
+		err := d.upload(u)
+		d.m.Lock()
+		d.uperr = err
+		d.m.Unlock()
+	}
+}
+
+// upload passes the snapshot file to the uploader. The file is replaced by a rename when a snapshot is written, so the
+// opened file stays complete.
+func (d *SnapDriver) upload(u Uploader) error {
+	f, err := os.Open(d.name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return u.Upload(path.Base(d.name)+"."+time.Now().UTC().Format("20060102T150405.000000000"), f)
+}
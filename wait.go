@@ -0,0 +1,62 @@
+package acdb
+
+import (
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// notify wakes up everyone waiting for a change of a key. The caller must hold the lock.
+func (e *Client) notify(k string) {
+	e.wm.Lock()
+	defer e.wm.Unlock()
+	if c, b := e.wait[k]; b {
+		close(c)
+		delete(e.wait, k)
+	}
+}
+
+// waiter returns the channel closed on the next change of a key.
+func (e *Client) waiter(k string) chan struct{} {
+	e.wm.Lock()
+	defer e.wm.Unlock()
+	c, b := e.wait[k]
+	if !b {
+		c = make(chan struct{})
+		e.wait[k] = c
+	}
+	return c
+}
+
+// GetWait returns the value of a key as soon as its checksum differs from sum, waiting up to timeout for a change. The
+// checksum is the one reported by Head, a key which does not exist has checksum 0. When the timeout expires, the
+// unchanged value is returned. This is a simple way for config pollers to wait for a change.
+func (e *Client) GetWait(k string, sum uint32, timeout time.Duration) ([]byte, error) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	for {
+		e.m.RLock()
+		r := e.resolve(k)
+		v, err := e.driver.Get(r)
+		if err != nil && !errors.Is(err, ErrNotExist) {
+			e.m.RUnlock()
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(v) != sum {
+			e.m.RUnlock()
+			return v, err
+		}
+		c := e.waiter(r)
+		a := c
+		if r != k {
+			a = e.waiter(k)
+		}
+		e.m.RUnlock()
+		select {
+		case <-c:
+		case <-a:
+		case <-t.C:
+			return v, err
+		}
+	}
+}
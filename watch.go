@@ -0,0 +1,69 @@
+package acdb
+
+import (
+	"time"
+)
+
+// Watch polls the root directory every interval and drops the cached value of every file that was added, modified or
+// removed since the previous poll, so values edited by hand or by other tools are never served stale for longer than
+// one interval. If f is not nil, it is called with the key of each change, including changes made through this driver.
+// It returns a function that stops watching.
+func (d *MapDriver) Watch(interval time.Duration, f func(k string)) func() {
+	type stat struct {
+		t time.Time
+		n int64
+	}
+	list := func() map[string]stat {
+		r := map[string]stat{}
+		l, err := d.doc.list()
+		if err != nil {
+			return r
+		}
+		for _, e := range l {
+			i, err := e.Info()
+			if err != nil {
+				continue
+			}
+			r[e.Name()] = stat{t: i.ModTime(), n: i.Size()}
+		}
+		return r
+	}
+	emit := func(k string) {
+		if d.bloom != nil {
+			d.bloom.add(k)
+		}
+		if d.neg != nil {
+			d.neg.Del(k)
+		}
+		d.lru.Del(k)
+		if f != nil {
+			f(k)
+		}
+	}
+	t := time.NewTicker(interval)
+	c := make(chan struct{})
+	go func() {
+		last := list()
+		for {
+			select {
+			case <-t.C:
+				next := list()
+				for k, s := range next {
+					if l, b := last[k]; !b || l != s {
+						emit(k)
+					}
+				}
+				for k := range last {
+					if _, b := next[k]; !b {
+						emit(k)
+					}
+				}
+				last = next
+			case <-c:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(c) }
+}
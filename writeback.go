@@ -0,0 +1,110 @@
+package acdb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// dirty is a write not yet flushed to the file system.
+type dirty struct {
+	v   []byte
+	del bool
+}
+
+// writeBack holds the pending writes of a MapDriver in write-back mode.
+type writeBack struct {
+	data map[string]*dirty
+	m    *sync.Mutex
+	f    *sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// get returns the pending write of a key.
+func (w *writeBack) get(k string) (*dirty, bool) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	e, b := w.data[k]
+	return e, b
+}
+
+// put records a pending write of a key, replacing any older one.
+func (w *writeBack) put(k string, e *dirty) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.data[k] = e
+}
+
+// WriteBack switches the driver to write-back mode. Sets and dels land in the cache immediately and are marked dirty,
+// and a background goroutine flushes them to the file system every interval. Writes not yet flushed are lost on a
+// crash, call Close to flush them on shutdown. It must be called before the driver is used.
+func (d *MapDriver) WriteBack(interval time.Duration) {
+	d.wb = &writeBack{
+		data: map[string]*dirty{},
+		m:    &sync.Mutex{},
+		f:    &sync.Mutex{},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(d.wb.done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				d.Flush()
+			case <-d.wb.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Flush writes all pending writes to the file system. Writes which fail stay pending and the last error is returned.
+func (d *MapDriver) Flush() error {
+	if d.wb == nil {
+		return nil
+	}
+	d.wb.f.Lock()
+	defer d.wb.f.Unlock()
+	d.wb.m.Lock()
+	l := make(map[string]*dirty, len(d.wb.data))
+	for k, e := range d.wb.data {
+		l[k] = e
+	}
+	d.wb.m.Unlock()
+	var r error
+	for k, e := range l {
+		var err error
+		if e.del {
+			err = d.doc.Del(k)
+			if errors.Is(err, ErrNotExist) {
+				err = nil
+			}
+		} else {
+			err = d.doc.Set(k, e.v)
+		}
+		if err != nil {
+			r = err
+			continue
+		}
+		d.wb.m.Lock()
+		if d.wb.data[k] == e {
+			delete(d.wb.data, k)
+		}
+		d.wb.m.Unlock()
+	}
+	return r
+}
+
+// Close stops the background flushing of write-back mode and flushes all pending writes.
+func (d *MapDriver) Close() error {
+	if d.wb == nil {
+		return nil
+	}
+	close(d.wb.stop)
+	<-d.wb.done
+	return d.Flush()
+}